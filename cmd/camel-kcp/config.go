@@ -0,0 +1,196 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+// defaultServiceConfiguration returns the default service configuration, that the configuration file overrides.
+// The defaults of the service specific fields are set by the configuration Default method.
+func defaultServiceConfiguration() *config.ServiceConfiguration {
+	return &config.ServiceConfiguration{
+		ControllerManagerConfigurationSpec: ctrlcfg.ControllerManagerConfigurationSpec{
+			Health: ctrlcfg.ControllerHealth{
+				HealthProbeBindAddress: ":8081",
+			},
+			Metrics: ctrlcfg.ControllerMetrics{
+				BindAddress: ":8080",
+			},
+			LeaderElection: &configv1alpha1.LeaderElectionConfiguration{
+				LeaderElect:       pointer.Bool(true),
+				ResourceLock:      resourcelock.LeasesResourceLock,
+				ResourceName:      "camel-kcp",
+				ResourceNamespace: "camel-kcp",
+				LeaseDuration:     metav1.Duration{Duration: 15 * time.Second},
+				RenewDeadline:     metav1.Duration{Duration: 10 * time.Second},
+				RetryPeriod:       metav1.Duration{Duration: 2 * time.Second},
+			},
+		},
+		Service: config.ServiceConfigurationSpec{
+			APIExports: config.APIExports{
+				CamelK: config.CamelKAPIExport{
+					LocalAPIExportReference: config.LocalAPIExportReference{
+						APIExportName: "camel-k",
+					},
+				},
+				Kaoto: config.KaotoAPIExport{
+					LocalAPIExportReference: config.LocalAPIExportReference{
+						APIExportName: "kaoto",
+					},
+				},
+			},
+		},
+	}
+}
+
+// loadServiceConfiguration loads the configuration file on top of the default service configuration.
+// The returned configuration is defaulted, but not validated.
+func loadServiceConfiguration(path string) (*config.ServiceConfiguration, error) {
+	svcCfg := defaultServiceConfiguration()
+	if _, err := ctrl.ConfigFile().AtPath(path).OfKind(svcCfg).Complete(); err != nil {
+		return nil, err
+	}
+	svcCfg.Default()
+	return svcCfg, nil
+}
+
+// validateConfigCommand implements the validate-config command, that loads and validates a configuration file,
+// and reports the invalid fields, if any. It returns the process exit code.
+func validateConfigCommand(args []string) int {
+	flagSet := flag.NewFlagSet("validate-config", flag.ContinueOnError)
+	path := flagSet.String("config", options.configFilePath, "The path of the configuration file to validate.")
+	if err := flagSet.Parse(args); err != nil {
+		return 2
+	}
+	if *path == "" {
+		fmt.Fprintln(os.Stderr, "the --config flag is required")
+		return 2
+	}
+
+	svcCfg, err := loadServiceConfiguration(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error loading %s: %v\n", *path, err)
+		return 1
+	}
+	if errs := svcCfg.Validate(); len(errs) > 0 {
+		fmt.Fprintf(os.Stderr, "%s is invalid:\n", *path)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "  %v\n", err)
+		}
+		return 1
+	}
+
+	fmt.Printf("%s is valid\n", *path)
+	return 0
+}
+
+// watchServiceConfiguration watches the configuration file, and reloads the service configuration when it changes.
+func watchServiceConfiguration(ctx context.Context, path string, holder *config.Holder) func() error {
+	return func() error {
+		if path == "" {
+			return nil
+		}
+
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("error creating configuration file watcher: %w", err)
+		}
+		defer watcher.Close()
+
+		// Watch the parent directory, as the file may be replaced rather than written,
+		// e.g., when it's mounted from a ConfigMap, the ..data symbolic link is swapped.
+		path = filepath.Clean(path)
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("error watching configuration file: %w", err)
+		}
+
+		logger.Info("Watching service configuration file", "path", path)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case e, ok := <-watcher.Events:
+				if !ok {
+					return nil
+				}
+				if e.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				if name := filepath.Clean(e.Name); name != path && filepath.Base(name) != "..data" {
+					continue
+				}
+				reloadServiceConfiguration(path, holder)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return nil
+				}
+				logger.Error(err, "error watching configuration file", "path", path)
+			}
+		}
+	}
+}
+
+// reloadServiceConfiguration loads and validates the configuration file, and swaps the current service configuration.
+// The current service configuration is kept if the configuration file is invalid.
+func reloadServiceConfiguration(path string, holder *config.Holder) {
+	svcCfg, err := loadServiceConfiguration(path)
+	if err != nil {
+		logger.Error(err, "error reloading service configuration, keeping the current one", "path", path)
+		return
+	}
+
+	if errs := svcCfg.Validate(); len(errs) > 0 {
+		logger.Error(errs.ToAggregate(), "invalid service configuration, keeping the current one", "path", path)
+		return
+	}
+
+	current := holder.Get()
+	if errs := svcCfg.ValidateUpdate(current); len(errs) > 0 {
+		logger.Error(errs.ToAggregate(), "invalid service configuration, keeping the current one", "path", path)
+		return
+	}
+
+	if equality.Semantic.DeepEqual(current, svcCfg) {
+		return
+	}
+
+	holder.Set(svcCfg)
+	logger.Info("Service configuration reloaded", "path", path)
+}
@@ -0,0 +1,236 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"golang.org/x/sync/errgroup"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	retrywatch "k8s.io/client-go/tools/watch"
+
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+// runFunc runs against the virtual workspace of a single shard, until the context is cancelled.
+type runFunc func(ctx context.Context, cfg *rest.Config) error
+
+// +kubebuilder:rbac:groups="apis.kcp.io",resources=apiexportendpointslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups="apis.kcp.io",resources=apiexports/content,verbs=get;list;watch;create;update;patch;delete
+
+// runForEachEndpoint watches the APIExportEndpointSlice with the given name, and calls run for each of the
+// APIExport virtual workspace URLs it lists, i.e., one per shard. A run is started when an endpoint is added
+// to the slice, and its context is cancelled when the endpoint is removed from the slice.
+// It returns when the context is cancelled, or as soon as one of the runs returns an error.
+func runForEachEndpoint(ctx context.Context, cfg *rest.Config, sliceName string, run runFunc) error {
+	return runForEachURL(ctx, cfg, urlSource{
+		kind: "APIExportEndpointSlice",
+		name: sliceName,
+		list: func() ctrlclient.ObjectList { return &apisv1alpha1.APIExportEndpointSliceList{} },
+		urls: func(obj runtime.Object) []string {
+			var urls []string
+			if slice, ok := obj.(*apisv1alpha1.APIExportEndpointSlice); ok {
+				for _, endpoint := range slice.Status.APIExportEndpoints {
+					urls = append(urls, endpoint.URL)
+				}
+			}
+			return urls
+		},
+	}, run)
+}
+
+// +kubebuilder:rbac:groups="tenancy.kcp.io",resources=workspacetypes,verbs=get;list;watch
+
+// runForEachInitializingEndpoint watches the WorkspaceType with the given name, and calls run for each of the
+// initialization virtual workspace URLs it lists, i.e., one per shard, like runForEachEndpoint does.
+func runForEachInitializingEndpoint(ctx context.Context, cfg *rest.Config, workspaceTypeName string, run runFunc) error {
+	return runForEachURL(ctx, cfg, urlSource{
+		kind: "WorkspaceType",
+		name: workspaceTypeName,
+		list: func() ctrlclient.ObjectList { return &tenancyv1alpha1.WorkspaceTypeList{} },
+		urls: func(obj runtime.Object) []string {
+			var urls []string
+			if wt, ok := obj.(*tenancyv1alpha1.WorkspaceType); ok {
+				for _, vw := range wt.Status.VirtualWorkspaces {
+					urls = append(urls, vw.URL)
+				}
+			}
+			return urls
+		},
+	}, run)
+}
+
+// workspaceConfig returns a copy of the given configuration, for the workspace with the given logical cluster path.
+func workspaceConfig(cfg *rest.Config, path logicalcluster.Path) (*rest.Config, error) {
+	u, err := url.Parse(cfg.Host)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing host %s: %w", cfg.Host, err)
+	}
+	u.Path = path.RequestPath()
+	workspaceCfg := rest.CopyConfig(cfg)
+	workspaceCfg.Host = u.String()
+	return workspaceCfg, nil
+}
+
+// urlSource describes the object, identified by its kind and name, that lists the virtual workspace URLs.
+type urlSource struct {
+	kind string
+	name string
+	list func() ctrlclient.ObjectList
+	urls func(runtime.Object) []string
+}
+
+func runForEachURL(ctx context.Context, cfg *rest.Config, source urlSource, run runFunc) error {
+	c, err := ctrlclient.NewWithWatch(cfg, ctrlclient.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("error creating %s client: %w", source.kind, err)
+	}
+
+	list := source.list()
+	selector := fields.OneTermEqualSelector("metadata.name", source.name)
+	err = c.List(ctx, list, ctrlclient.MatchingFieldsSelector{Selector: selector})
+	if err != nil {
+		return fmt.Errorf("error listing %s: %w", source.kind, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	endpoints := &endpointRunner{
+		ctx:    groupCtx,
+		group:  group,
+		cfg:    cfg,
+		source: source,
+		run:    run,
+		runs:   make(map[string]context.CancelFunc),
+	}
+
+	if len(items) > 0 {
+		endpoints.sync(source.urls(items[0]))
+	}
+
+	w := watcher{watch: c.Watch, list: source.list}
+	rw, err := retrywatch.NewRetryWatcher(list.GetResourceVersion(), w.FilteredBy(selector))
+	if err != nil {
+		return fmt.Errorf("error creating retry watcher for %s: %w", source.kind, err)
+	}
+	defer rw.Stop()
+
+	logger.Info("Watching for virtual workspace URLs", source.kind, source.name)
+
+	group.Go(func() error {
+		for {
+			select {
+			case <-groupCtx.Done():
+				return nil
+			case e, ok := <-rw.ResultChan():
+				if !ok {
+					return fmt.Errorf("%s watch closed unexpectedly", source.kind)
+				}
+				switch e.Type {
+				case watch.Error:
+					return fmt.Errorf("error watching for %s: %w", source.kind, apierrors.FromObject(e.Object))
+
+				case watch.Added, watch.Modified:
+					endpoints.sync(source.urls(e.Object))
+
+				case watch.Deleted:
+					endpoints.sync(nil)
+				}
+			}
+		}
+	})
+
+	return group.Wait()
+}
+
+type endpointRunner struct {
+	// nolint: containedctx
+	ctx    context.Context
+	group  *errgroup.Group
+	cfg    *rest.Config
+	source urlSource
+	run    runFunc
+	runs   map[string]context.CancelFunc
+}
+
+// sync starts runs for the URLs that have been added,
+// and cancels the runs for the URLs that have been removed.
+func (r *endpointRunner) sync(list []string) {
+	urls := sets.NewString(list...)
+
+	for u, cancel := range r.runs {
+		if !urls.Has(u) {
+			logger.Info("Stopping for removed virtual workspace URL", r.source.kind, r.source.name, "url", u)
+			cancel()
+			delete(r.runs, u)
+		}
+	}
+
+	if urls.Len() == 0 {
+		logger.Info("No virtual workspace URLs", r.source.kind, r.source.name)
+		return
+	}
+
+	for _, u := range urls.List() {
+		if _, ok := r.runs[u]; ok {
+			continue
+		}
+		logger.Info("Starting for virtual workspace URL", r.source.kind, r.source.name, "url", u)
+		ctx, cancel := context.WithCancel(r.ctx)
+		r.runs[u] = cancel
+		cfg := rest.CopyConfig(r.cfg)
+		cfg.Host = u
+		r.group.Go(func() error {
+			defer cancel()
+			return r.run(ctx, cfg)
+		})
+	}
+}
+
+type watcher struct {
+	watch func(ctx context.Context, obj ctrlclient.ObjectList, opts ...ctrlclient.ListOption) (watch.Interface, error)
+	list  func() ctrlclient.ObjectList
+	opts  []ctrlclient.ListOption
+}
+
+func (w watcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return w.watch(context.TODO(), w.list(), append([]ctrlclient.ListOption{&ctrlclient.ListOptions{Raw: &options}}, w.opts...)...)
+}
+
+func (w watcher) FilteredBy(selector fields.Selector) watcher {
+	w.opts = append(w.opts, ctrlclient.MatchingFieldsSelector{Selector: selector})
+	return w
+}
@@ -0,0 +1,149 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// resetBackoffAfter is the duration after which a set of controllers, that has been running, is considered healthy,
+// so that it's restarted without delay if it fails afterwards.
+const resetBackoffAfter = 5 * time.Minute
+
+var controllersRestarts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "camel_kcp_controllers_restarts_total",
+		Help: "Total number of restarts of the sets of controllers, by name.",
+	},
+	[]string{"name"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(controllersRestarts)
+}
+
+// controllers is a named set of controllers, that runs until the context is cancelled, or it fails.
+type controllers struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// exportManager hosts multiple sets of controllers, e.g., backed by APIExports, behind the same process lifecycle.
+// Each set of controllers runs independently, and is restarted with backoff when it fails, so that a failing
+// set does not bring down the others. The metrics and health probes are served once, for all of them.
+type exportManager struct {
+	cfg         *rest.Config
+	backoff     wait.Backoff
+	controllers []controllers
+	readiness   []*apiExportReadiness
+}
+
+func newExportManager(cfg *rest.Config) *exportManager {
+	return &exportManager{
+		cfg: cfg,
+		backoff: wait.Backoff{
+			Duration: time.Second,
+			Factor:   2,
+			Jitter:   0.1,
+			Steps:    math.MaxInt32,
+			Cap:      5 * time.Minute,
+		},
+	}
+}
+
+// AddAPIExport adds the controllers started by start, for each of the virtual workspaces of the APIExport,
+// listed by the APIExportEndpointSlice with the given name. The readiness of the virtual workspaces is tracked,
+// while the controllers run against them.
+func (m *exportManager) AddAPIExport(name string, sliceName string, readiness *apiExportReadiness, start runFunc) {
+	m.readiness = append(m.readiness, readiness)
+	m.Add(name, func(ctx context.Context) error {
+		return runForEachEndpoint(ctx, m.cfg, sliceName, readiness.track(start))
+	})
+}
+
+// Add adds a named set of controllers, run by the given function.
+func (m *exportManager) Add(name string, run func(ctx context.Context) error) {
+	m.controllers = append(m.controllers, controllers{name: name, run: run})
+}
+
+// ReadyzChecks returns the readiness checks of all the APIExports, keyed by name. The controllers only run
+// while the process leads, so that the checks pass when it does not, and standby replicas are ready.
+func (m *exportManager) ReadyzChecks(leader *leaderStatus) map[string]healthz.Checker {
+	checks := make(map[string]healthz.Checker)
+	for _, readiness := range m.readiness {
+		for name, check := range readiness.Checks() {
+			checks[name] = whenLeading(leader, check)
+		}
+	}
+	return checks
+}
+
+// Start runs all the sets of controllers, and blocks until the context is cancelled.
+func (m *exportManager) Start(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, c := range m.controllers {
+		c := c
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.runWithRestart(ctx, c)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// runWithRestart runs the set of controllers, and restarts it with backoff, until the context is cancelled.
+func (m *exportManager) runWithRestart(ctx context.Context, c controllers) {
+	backoff := m.backoff
+	for {
+		started := time.Now()
+		err := c.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > resetBackoffAfter {
+			backoff = m.backoff
+		}
+		delay := backoff.Step()
+		if err != nil {
+			logger.Error(err, "Controllers failed, restarting", "name", c.name, "delay", delay)
+		} else {
+			logger.Info("Controllers exited, restarting", "name", c.name, "delay", delay)
+		}
+		controllersRestarts.WithLabelValues(c.name).Inc()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+const (
+	operatorImageEnvVariable = "OPERATOR_IMAGE"
+	podNameEnvVariable       = "POD_NAME"
+	podNamespaceEnvVariable  = "POD_NAMESPACE"
+
+	operatorContainerName = "manager"
+)
+
+// operatorImage returns the container image of the operator, from the service configuration, the OPERATOR_IMAGE
+// environment variable, or the operator Pod, in that order. It returns an empty string if it cannot be determined,
+// e.g., when running out of cluster.
+func operatorImage(ctx context.Context, svcCfg *config.ServiceConfiguration) (string, error) {
+	if image := svcCfg.Service.OperatorImage; image != "" {
+		return image, nil
+	}
+	if image := os.Getenv(operatorImageEnvVariable); image != "" {
+		return image, nil
+	}
+	return operatorPodImage(ctx)
+}
+
+// operatorPodImage returns the container image of the operator Pod, identified by the POD_NAME and POD_NAMESPACE
+// environment variables, that are expected to be set using the downward API. The Pod is retrieved from the cluster
+// the operator runs into, using the in-cluster configuration, rather than from kcp.
+func operatorPodImage(ctx context.Context) (string, error) {
+	name, namespace := os.Getenv(podNameEnvVariable), os.Getenv(podNamespaceEnvVariable)
+	if name == "" || namespace == "" {
+		return "", nil
+	}
+
+	cfg, err := rest.InClusterConfig()
+	if errors.Is(err, rest.ErrNotInCluster) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+		// The in-cluster API server may not serve the operator Pod, e.g., when it's deployed by a kcp syncer
+		logger.Info("Cannot retrieve the operator Pod, set the OPERATOR_IMAGE environment variable instead",
+			"namespace", namespace, "name", name, "error", err.Error())
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.Name == operatorContainerName {
+			return container.Image, nil
+		}
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return "", fmt.Errorf("no containers found in operator pod")
+	}
+	return pod.Spec.Containers[0].Image, nil
+}
@@ -0,0 +1,187 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var leaderGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "camel_kcp_leader",
+		Help: "Whether the process currently holds the leader election lock, i.e., 1 when leading, 0 otherwise.",
+	},
+)
+
+func init() {
+	metrics.Registry.MustRegister(leaderGauge)
+}
+
+// leaderStatus tracks whether the process currently holds the leader election lock.
+// The leadership is reported by the camel_kcp_leader metric, and by the /readyz/leader endpoint, that's
+// excluded from the aggregated readiness, i.e., the readiness checks only apply while leading, so that
+// standby replicas are ready, and rolling updates can proceed while the old replica leads.
+type leaderStatus struct {
+	leading atomic.Bool
+}
+
+func (l *leaderStatus) set(leading bool) {
+	l.leading.Store(leading)
+	if leading {
+		leaderGauge.Set(1)
+	} else {
+		leaderGauge.Set(0)
+	}
+}
+
+// Check is the readiness check, that fails unless the process holds the leader election lock.
+func (l *leaderStatus) Check(_ *http.Request) error {
+	if !l.leading.Load() {
+		return errors.New("not leading")
+	}
+	return nil
+}
+
+// whenLeading returns a checker that only runs the given check when the process leads, and passes otherwise.
+func whenLeading(leader *leaderStatus, check healthz.Checker) healthz.Checker {
+	return func(req *http.Request) error {
+		if !leader.leading.Load() {
+			return nil
+		}
+		return check(req)
+	}
+}
+
+// +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;patch
+
+// runWithLeaderElection calls run once the leader election lock is acquired. The lock is a Lease
+// created in the service workspace, so that it's shared by all the managers, whatever the APIExport
+// virtual workspace they run against. The run context is cancelled when the leadership is lost,
+// and the lock is only released once run has returned, to gracefully hand off to another replica.
+// It returns an error if the leadership is lost before the context is cancelled.
+func runWithLeaderElection(ctx context.Context, cfg *rest.Config, le *configv1alpha1.LeaderElectionConfiguration, status *leaderStatus, run func(context.Context) error) error {
+	if le == nil || le.LeaderElect == nil || !*le.LeaderElect {
+		logger.Info("Leader election is disabled")
+		status.set(true)
+		defer status.set(false)
+		return run(ctx)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	id := hostname + "_" + string(uuid.NewUUID())
+
+	lock, err := resourcelock.NewFromKubeconfig(le.ResourceLock, le.ResourceNamespace, le.ResourceName,
+		resourcelock.ResourceLockConfig{Identity: id}, cfg, le.RenewDeadline.Duration)
+	if err != nil {
+		return fmt.Errorf("error creating leader election lock: %w", err)
+	}
+
+	// The elector is stopped once run has returned, so that the lock is released last
+	electorCtx, stopElector := context.WithCancel(context.Background())
+	defer stopElector()
+
+	done := make(chan error, 1)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   le.LeaseDuration.Duration,
+		RenewDeadline:   le.RenewDeadline.Duration,
+		RetryPeriod:     le.RetryPeriod.Duration,
+		ReleaseOnCancel: true,
+		Name:            le.ResourceName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				logger.Info("Started leading", "identity", id)
+				status.set(true)
+				runCtx, cancel := context.WithCancel(ctx)
+				go func() {
+					select {
+					case <-leaderCtx.Done():
+						cancel()
+					case <-runCtx.Done():
+					}
+				}()
+				err := run(runCtx)
+				cancel()
+				done <- err
+				status.set(false)
+				stopElector()
+			},
+			OnStoppedLeading: func() {
+				logger.Info("Stopped leading", "identity", id)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != id {
+					logger.Info("New leader elected", "identity", identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating leader elector: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		// Stop competing for the lock if not leading, otherwise let run return first
+		if !status.leading.Load() {
+			stopElector()
+		}
+	}()
+
+	logger.Info("Starting leader election", "namespace", le.ResourceNamespace, "name", le.ResourceName, "identity", id)
+	elector.Run(electorCtx)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	default:
+		if status.leading.Load() {
+			// The leadership has been lost while running, wait for run to return
+			if err := <-done; err != nil {
+				return err
+			}
+		}
+	}
+
+	if ctx.Err() == nil {
+		return errors.New("leader election lost")
+	}
+
+	return nil
+}
@@ -39,53 +39,64 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
-	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/leaderelection/resourcelock"
-	"k8s.io/client-go/tools/record"
-	retrywatch "k8s.io/client-go/tools/watch"
-	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/pointer"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
-	ctrlcfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
-	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/kcp"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/kcp-dev/logicalcluster/v3"
+
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/tenancy/initialization"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	sourcesv1 "knative.dev/eventing/pkg/apis/sources/v1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"github.com/apache/camel-k/pkg/apis"
 	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	camelk "github.com/apache/camel-k/pkg/controller"
-	"github.com/apache/camel-k/pkg/event"
 	"github.com/apache/camel-k/pkg/util/defaults"
 	logutil "github.com/apache/camel-k/pkg/util/log"
 
 	"github.com/apache/camel-kcp/pkg/client"
 	"github.com/apache/camel-kcp/pkg/config"
 	"github.com/apache/camel-kcp/pkg/controller"
+	"github.com/apache/camel-kcp/pkg/event"
 	"github.com/apache/camel-kcp/pkg/platform"
 )
 
 var scheme = runtime.NewScheme()
 
+// claimedObjects are the objects managed by Camel K, whose APIs are provided by other APIExports, and claimed
+// by the Camel K APIExport, depending on the installation, and may only be bound in some of the consumer workspaces.
+var claimedObjects = []ctrlclient.Object{
+	&batchv1.Job{},
+	&batchv1.CronJob{},
+	&servingv1.Service{},
+	&eventingv1.Broker{},
+	&eventingv1.Trigger{},
+	&messagingv1.Channel{},
+	&messagingv1.Subscription{},
+	&sourcesv1.SinkBinding{},
+}
+
 var logger = logutil.Log.WithName("kcp")
 
 var options struct {
@@ -130,6 +141,15 @@ func printVersion() {
 }
 
 func main() {
+	if flag.NArg() > 0 {
+		switch command := flag.Arg(0); command {
+		case "validate-config":
+			os.Exit(validateConfigCommand(flag.Args()[1:]))
+		default:
+			exitOnError(fmt.Errorf("unknown command %q", command), "")
+		}
+	}
+
 	printVersion()
 
 	rand.Seed(time.Now().UTC().UnixNano())
@@ -141,6 +161,8 @@ func main() {
 	exitOnError(clientgoscheme.AddToScheme(scheme), "failed registering types to scheme")
 	exitOnError(apis.AddToScheme(scheme), "failed registering types to scheme")
 	exitOnError(apisv1alpha1.AddToScheme(scheme), "failed registering types to scheme")
+	exitOnError(corev1alpha1.AddToScheme(scheme), "failed registering types to scheme")
+	exitOnError(tenancyv1alpha1.AddToScheme(scheme), "failed registering types to scheme")
 
 	// Configuration
 	hasIntegrationLabel, err := labels.NewRequirement(v1.IntegrationLabel, selection.Exists, []string{})
@@ -154,65 +176,39 @@ func main() {
 		&batchv1.CronJob{}:   {Label: selector},
 	}
 
-	// FIXME: cluster-aware event sink
-	broadcaster := record.NewBroadcaster()
-	broadcaster = event.NewSinkLessBroadcaster(broadcaster)
-
-	// FIXME: enable leader election
 	mgrOptions := ctrl.Options{
-		LeaderElectionConfig:          cfg,
-		LeaderElectionReleaseOnCancel: true,
-		Scheme:                        scheme,
-		EventBroadcaster:              broadcaster,
+		Scheme: scheme,
 		NewCache: func(config *rest.Config, options cache.Options) (cache.Cache, error) {
 			options.SelectorsByObject = selectors
 			return kcp.NewClusterAwareCache(config, options)
 		},
 	}
 
-	svcCfg := &config.ServiceConfiguration{
-		ControllerManagerConfigurationSpec: ctrlcfg.ControllerManagerConfigurationSpec{
-			Health: ctrlcfg.ControllerHealth{
-				HealthProbeBindAddress: ":8081",
-			},
-			Metrics: ctrlcfg.ControllerMetrics{
-				BindAddress: ":8080",
-			},
-			LeaderElection: &configv1alpha1.LeaderElectionConfiguration{
-				LeaderElect:  pointer.Bool(false),
-				ResourceLock: resourcelock.LeasesResourceLock,
-			},
-		},
-		Service: config.ServiceConfigurationSpec{
-			APIExports: config.APIExports{
-				CamelK: config.CamelKAPIExport{
-					LocalAPIExportReference: config.LocalAPIExportReference{
-						APIExportName: "camel-k",
-					},
-				},
-				Kaoto: config.KaotoAPIExport{
-					LocalAPIExportReference: config.LocalAPIExportReference{
-						APIExportName: "kaoto",
-					},
-				},
-			},
-		},
-	}
+	svcCfg := defaultServiceConfiguration()
 
 	if options.configFilePath != "" {
 		mgrOptions, err = mgrOptions.AndFrom(ctrl.ConfigFile().AtPath(options.configFilePath).OfKind(svcCfg))
 		exitOnError(err, "error loading controller configuration")
 	}
+	svcCfg.Default()
+	exitOnError(svcCfg.Validate().ToAggregate(), "invalid service configuration")
 
 	// Environment
 	_, err = maxprocs.Set(maxprocs.Logger(func(f string, a ...interface{}) { logger.Info(fmt.Sprintf(f, a)) }))
 	exitOnError(err, "failed to set GOMAXPROCS from cgroups")
 
-	if ip := svcCfg.Service.APIExports.CamelK.OnAPIBinding.DefaultPlatform; ip != nil && ip.Namespace != "" {
-		exitOnError(os.Setenv(platform.OperatorNamespaceEnvVariable, ip.Namespace), "")
-	} else {
-		exitOnError(os.Setenv(platform.OperatorNamespaceEnvVariable, platform.DefaultNamespaceName), "")
+	// The operator namespace is resolved for each logical cluster, so that Camel K must not fall back to
+	// the global operator namespace from the environment, when an integration platform is not found in the
+	// resource namespace, but to that of the logical cluster, that the client resolves
+	exitOnError(os.Unsetenv(platform.OperatorNamespaceEnvVariable), "")
+
+	// Set the operator container image, e.g., used by the builder Pods
+	image, err := operatorImage(ctx, svcCfg)
+	exitOnError(err, "cannot get operator container image")
+	if image != "" {
+		logger.Info("Using operator container image", "image", image)
 	}
+	platform.SetOperatorImage(image)
 
 	// Bootstrap
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
@@ -222,39 +218,166 @@ func main() {
 		exitOnError(fmt.Errorf("%s group is not present", apisv1alpha1.SchemeGroupVersion.Group), "")
 	}
 
+	// Metrics and health probes are served once for all the managers, as they may run for multiple shards
+	metricsBindAddress, healthProbeBindAddress := mgrOptions.MetricsBindAddress, mgrOptions.HealthProbeBindAddress
+	mgrOptions.MetricsBindAddress = "0"
+	mgrOptions.HealthProbeBindAddress = "0"
+	// Leader election is performed once for all the managers, in the service workspace
+	mgrOptions.LeaderElection = false
+
+	// The provisioning status of the consumer workspaces is aggregated in the service workspace
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	exitOnError(err, "failed to create Kubernetes client")
+	status := controller.NewStatusReporter(kubeClient, svcCfg.Service.StatusNamespace)
+	// The paths of the consumer workspaces, that the overrides select, are recorded into the service workspace
+	paths := controller.NewWorkspacePaths(kubeClient, svcCfg.Service.StatusNamespace)
+	// The inventories of the resources applied from the manifests are recorded into the service workspace
+	inventory := controller.NewManifestInventory(kubeClient, svcCfg.Service.StatusNamespace)
+	// The registry credentials provisioned into the consumer workspaces are read from the service workspace
+	registry := controller.NewSecretRegistryProvisioner(kubeClient)
+
+	// The service configuration is reloaded when the configuration file changes
+	svcCfgHolder := config.NewHolder(svcCfg)
+
+	leader := &leaderStatus{}
+
+	// The controllers of each APIExport are run and restarted independently, within the same process lifecycle.
+	// The readiness of the managers reflects the state of the APIExport virtual workspaces they run against.
+	exports := newExportManager(cfg)
+
+	camelK := svcCfg.Service.APIExports.CamelK
+	camelKReadiness := newAPIExportReadiness(camelK.APIExportName)
+	exports.AddAPIExport(camelK.APIExportName, camelK.EndpointSliceName(), camelKReadiness,
+		startCamelKManager(svcCfgHolder, mgrOptions, status, paths, inventory, registry, camelKReadiness))
+
+	kaoto := svcCfg.Service.APIExports.Kaoto
+	kaotoReadiness := newAPIExportReadiness(kaoto.APIExportName)
+	exports.AddAPIExport(kaoto.APIExportName, kaoto.EndpointSliceName(), kaotoReadiness,
+		startKaotoManager(svcCfgHolder, status, paths, inventory, kaotoReadiness))
+
+	for _, addOn := range svcCfg.Service.APIExports.AddOns {
+		addOnReadiness := newAPIExportReadiness(addOn.APIExportName)
+		exports.AddAPIExport(addOn.APIExportName, addOn.EndpointSliceName(), addOnReadiness,
+			startAddOnManager(svcCfgHolder, status, paths, inventory, addOn.APIExportName, addOnReadiness))
+	}
+
+	// The consumer workspaces are released once they are provisioned
+	if initializer := svcCfg.Service.Initializer; initializer != nil {
+		for _, workspaceType := range initializer.WorkspaceTypes {
+			workspaceType := workspaceType
+			exports.Add("initializer-"+logicalcluster.NewPath(workspaceType.Path).Join(workspaceType.Name).String(), func(ctx context.Context) error {
+				workspaceTypeCfg, err := workspaceConfig(cfg, logicalcluster.NewPath(workspaceType.Path))
+				if err != nil {
+					return err
+				}
+				return runForEachInitializingEndpoint(ctx, workspaceTypeCfg, workspaceType.Name,
+					startInitializerManager(svcCfgHolder, paths, workspaceTypeCfg, workspaceType.Name))
+			})
+		}
+	}
+
+	readyzChecks := exports.ReadyzChecks(leader)
+
 	group, groupCtx := errgroup.WithContext(ctx)
 
-	// TODO: revisit if/when controller-runtime supports multiple clusters / clients
-	group.Go(startCamelKManager(groupCtx, cfg, svcCfg, mgrOptions))
-	group.Go(startKaotoManager(groupCtx, cfg, svcCfg, broadcaster))
+	group.Go(watchServiceConfiguration(groupCtx, options.configFilePath, svcCfgHolder))
+	group.Go(serveMetrics(groupCtx, metricsBindAddress))
+	group.Go(serveHealthProbes(groupCtx, healthProbeBindAddress, readyzChecks, leader.Check))
+
+	group.Go(func() error {
+		return runWithLeaderElection(groupCtx, cfg, svcCfg.LeaderElection, leader, exports.Start)
+	})
 
 	exitOnError(group.Wait(), "managers exited non-zero")
 }
 
-func startCamelKManager(ctx context.Context, cfg *rest.Config, svcCfg *config.ServiceConfiguration, mgrOptions manager.Options) func() error {
-	return func() error {
-		logger.Info("Looking up Camel K virtual workspace URL")
-		apiExportCfg, err := restConfigForAPIExport(ctx, cfg, svcCfg.Service.APIExports.CamelK.APIExportName)
+func startCamelKManager(svcCfg *config.Holder, mgrOptions manager.Options, status *controller.StatusReporter, paths *controller.WorkspacePaths, inventory *controller.ManifestInventory, registry controller.RegistryProvisioner, readiness *apiExportReadiness) runFunc {
+	return func(ctx context.Context, apiExportCfg *rest.Config) error {
+		logger.Info("Using Camel K virtual workspace URL", "url", apiExportCfg.Host)
+
+		logger.Info("Configuring the Camel K manager", "url", apiExportCfg.Host)
+		broadcaster := event.NewClusterAwareBroadcaster()
+		defer broadcaster.Shutdown()
+		options := mgrOptions
+		options.EventBroadcaster = broadcaster
+		// The objects whose APIs are not served by the virtual workspace, i.e., that the APIExport does not claim,
+		// are read directly from the API server, as their informers would never sync
+		absent, err := absentObjects(apiExportCfg, claimedObjects...)
 		if err != nil {
 			return err
 		}
-		logger.Info("Using Camel K virtual workspace URL", "url", apiExportCfg.Host)
+		if len(absent) > 0 {
+			logger.Info("Disabling cache for objects whose APIs are not served", "url", apiExportCfg.Host, "objects", len(absent))
+		}
+		options.ClientDisableCacheFor = append(append([]ctrlclient.Object{}, options.ClientDisableCacheFor...), absent...)
+		mgr, err := kcp.NewClusterAwareManager(apiExportCfg, options)
+		if err != nil {
+			return err
+		}
+		c, err := client.NewClient(apiExportCfg, scheme, mgr.GetClient())
+		if err != nil {
+			return err
+		}
+		c = client.WithOperatorNamespace(c, controller.CamelKOperatorNamespace(svcCfg, paths))
+		broadcaster.StartRecordingToSink(event.NewClusterAwareSink(c.CoreV1()))
+		err = camelk.AddToManager(ctx, mgr, c)
+		if err != nil {
+			return err
+		}
+		err = controller.AddCamelKController(mgr, c, svcCfg, status, paths, inventory, registry)
+		if err != nil {
+			return err
+		}
+		err = controller.AddIntegrationMetricsController(mgr)
+		if err != nil {
+			return err
+		}
+		err = readiness.waitForCacheSync(mgr, apiExportCfg.Host)
+		if err != nil {
+			return err
+		}
+		logger.Info("Starting the Camel K manager", "url", apiExportCfg.Host)
+		return mgr.Start(ctx)
+	}
+}
 
-		// Set the operator container image if it runs in-container
-		// FIXME: find a way to retrieve the image
-		// platform.OperatorImage, err = getOperatorImage(ctx, c)
-		// exitOnError(err, "cannot get operator container image")
+func startKaotoManager(svcCfg *config.Holder, status *controller.StatusReporter, paths *controller.WorkspacePaths, inventory *controller.ManifestInventory, readiness *apiExportReadiness) runFunc {
+	return func(ctx context.Context, apiExportCfg *rest.Config) error {
+		logger.Info("Using Kaoto virtual workspace URL", "url", apiExportCfg.Host)
 
-		logger.Info("Configuring the Camel K manager")
-		mgr, err := kcp.NewClusterAwareManager(apiExportCfg, mgrOptions)
+		logger.Info("Configuring the Kaoto manager", "url", apiExportCfg.Host)
+		// The resource exposing the Kaoto UI is watched, so that its API must be served by the virtual workspace,
+		// i.e., the Kaoto APIExport must claim it, otherwise the caches would never sync
+		ingress := controller.KaotoIngressObject(&svcCfg.Get().Service.APIExports.Kaoto.OnAPIBinding.Kaoto)
+		absent, err := absentObjects(apiExportCfg, ingress)
 		if err != nil {
 			return err
 		}
-		err = mgr.AddHealthzCheck("healthz", healthz.Ping)
+		if len(absent) > 0 {
+			gvk, err := apiutil.GVKForObject(ingress, scheme)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("%s API is not served by the Kaoto virtual workspace, it must be claimed by the Kaoto APIExport", gvk.GroupKind())
+		}
+		// Only cache the Kaoto resources, rather than all the claimed resources of the consumer workspaces
+		selectors, err := controller.KaotoCacheSelectors(&svcCfg.Get().Service.APIExports.Kaoto.OnAPIBinding.Kaoto)
 		if err != nil {
 			return err
 		}
-		err = mgr.AddReadyzCheck("readyz", healthz.Ping)
+		broadcaster := event.NewClusterAwareBroadcaster()
+		defer broadcaster.Shutdown()
+		mgr, err := kcp.NewClusterAwareManager(apiExportCfg, ctrl.Options{
+			LeaderElection:         false,
+			MetricsBindAddress:     "0",
+			HealthProbeBindAddress: "0",
+			Scheme:                 scheme,
+			EventBroadcaster:       broadcaster,
+			NewCache: func(config *rest.Config, options cache.Options) (cache.Cache, error) {
+				options.SelectorsByObject = selectors
+				return kcp.NewClusterAwareCache(config, options)
+			},
+		})
 		if err != nil {
 			return err
 		}
@@ -262,34 +385,46 @@ func startCamelKManager(ctx context.Context, cfg *rest.Config, svcCfg *config.Se
 		if err != nil {
 			return err
 		}
-		err = camelk.AddToManager(ctx, mgr, c)
+		broadcaster.StartRecordingToSink(event.NewClusterAwareSink(c.CoreV1()))
+		err = controller.AddKaotoController(mgr, c, svcCfg, status, paths, inventory)
+		if err != nil {
+			return err
+		}
+		err = controller.AddKaotoIngressController(mgr, c, svcCfg)
 		if err != nil {
 			return err
 		}
-		err = controller.AddCamelKController(mgr, c, svcCfg)
+		err = readiness.waitForCacheSync(mgr, apiExportCfg.Host)
 		if err != nil {
 			return err
 		}
-		logger.Info("Starting the Camel K manager")
+		logger.Info("Starting the Kaoto manager", "url", apiExportCfg.Host)
 		return mgr.Start(ctx)
 	}
 }
 
-func startKaotoManager(ctx context.Context, cfg *rest.Config, svcCfg *config.ServiceConfiguration, broadcaster record.EventBroadcaster) func() error {
-	return func() error {
-		logger.Info("Looking up Kaoto virtual workspace URL")
-		apiExportCfg, err := restConfigForAPIExport(ctx, cfg, svcCfg.Service.APIExports.Kaoto.APIExportName)
+func startAddOnManager(svcCfg *config.Holder, status *controller.StatusReporter, paths *controller.WorkspacePaths, inventory *controller.ManifestInventory, apiExportName string, readiness *apiExportReadiness) runFunc {
+	return func(ctx context.Context, apiExportCfg *rest.Config) error {
+		logger.Info("Using add-on virtual workspace URL", "api-export", apiExportName, "url", apiExportCfg.Host)
+
+		logger.Info("Configuring the add-on manager", "api-export", apiExportName, "url", apiExportCfg.Host)
+		// Only cache the Kaoto resources, rather than all the claimed resources of the consumer workspaces
+		selectors, err := controller.KaotoCacheSelectors(&svcCfg.Get().Service.APIExports.Kaoto.OnAPIBinding.Kaoto)
 		if err != nil {
 			return err
 		}
-		logger.Info("Using Kaoto virtual workspace URL", "url", apiExportCfg.Host)
-
-		logger.Info("Configuring Kaoto the manager")
+		broadcaster := event.NewClusterAwareBroadcaster()
+		defer broadcaster.Shutdown()
 		mgr, err := kcp.NewClusterAwareManager(apiExportCfg, ctrl.Options{
-			LeaderElection:     false,
-			MetricsBindAddress: "0",
-			Scheme:             scheme,
-			EventBroadcaster:   broadcaster,
+			LeaderElection:         false,
+			MetricsBindAddress:     "0",
+			HealthProbeBindAddress: "0",
+			Scheme:                 scheme,
+			EventBroadcaster:       broadcaster,
+			NewCache: func(config *rest.Config, options cache.Options) (cache.Cache, error) {
+				options.SelectorsByObject = selectors
+				return kcp.NewClusterAwareCache(config, options)
+			},
 		})
 		if err != nil {
 			return err
@@ -298,93 +433,78 @@ func startKaotoManager(ctx context.Context, cfg *rest.Config, svcCfg *config.Ser
 		if err != nil {
 			return err
 		}
-		err = controller.AddKaotoController(mgr, c, svcCfg)
+		broadcaster.StartRecordingToSink(event.NewClusterAwareSink(c.CoreV1()))
+		err = controller.AddAddOnController(mgr, c, svcCfg, status, paths, inventory, apiExportName)
 		if err != nil {
 			return err
 		}
-		err = controller.AddKaotoIngressController(mgr, c, svcCfg)
+		err = readiness.waitForCacheSync(mgr, apiExportCfg.Host)
 		if err != nil {
 			return err
 		}
-		logger.Info("Starting the Kaoto manager")
+		logger.Info("Starting the add-on manager", "api-export", apiExportName, "url", apiExportCfg.Host)
 		return mgr.Start(ctx)
 	}
 }
 
-// +kubebuilder:rbac:groups="apis.kcp.io",resources=apiexports,verbs=get;list;watch
-// +kubebuilder:rbac:groups="apis.kcp.io",resources=apiexports/content,verbs=get;list;watch;create;update;patch;delete
+func startInitializerManager(svcCfg *config.Holder, paths *controller.WorkspacePaths, workspaceTypeCfg *rest.Config, workspaceTypeName string) runFunc {
+	return func(ctx context.Context, initializingCfg *rest.Config) error {
+		logger.Info("Using initializing workspaces virtual workspace URL", "url", initializingCfg.Host)
 
-// restConfigForAPIExport returns a *rest.Config properly configured to communicate with the endpoint for the
-// APIExport's virtual workspace. It blocks until the controller APIExport VirtualWorkspaceURLsReady condition
-// becomes truthy, which happens when the APIExport is bound for the first time.
-func restConfigForAPIExport(ctx context.Context, cfg *rest.Config, apiExportName string) (*rest.Config, error) {
-	apiExportClient, err := ctrlclient.NewWithWatch(cfg, ctrlclient.Options{Scheme: scheme})
-	if err != nil {
-		return nil, fmt.Errorf("error creating APIExport client: %w", err)
+		c, err := ctrlclient.New(workspaceTypeCfg, ctrlclient.Options{Scheme: scheme})
+		if err != nil {
+			return err
+		}
+		workspaceType := &tenancyv1alpha1.WorkspaceType{}
+		if err := c.Get(ctx, ctrlclient.ObjectKey{Name: workspaceTypeName}, workspaceType); err != nil {
+			return fmt.Errorf("error getting WorkspaceType %s: %w", workspaceTypeName, err)
+		}
+		initializer := initialization.InitializerForType(workspaceType)
+
+		logger.Info("Configuring the initializer manager", "url", initializingCfg.Host, "initializer", initializer)
+		mgr, err := kcp.NewClusterAwareManager(initializingCfg, ctrl.Options{
+			LeaderElection:         false,
+			MetricsBindAddress:     "0",
+			HealthProbeBindAddress: "0",
+			Scheme:                 scheme,
+		})
+		if err != nil {
+			return err
+		}
+		err = controller.AddInitializerController(mgr, svcCfg, paths, initializer)
+		if err != nil {
+			return err
+		}
+		logger.Info("Starting the initializer manager", "url", initializingCfg.Host, "initializer", initializer)
+		return mgr.Start(ctx)
 	}
+}
 
-	list := &apisv1alpha1.APIExportList{}
-	selector := fields.OneTermEqualSelector("metadata.name", apiExportName)
-	err = apiExportClient.List(ctx, list, ctrlclient.MatchingFieldsSelector{Selector: selector})
+// absentObjects returns the objects, whose APIs are served in none of the logical clusters of the virtual workspace.
+func absentObjects(apiExportCfg *rest.Config, objects ...ctrlclient.Object) ([]ctrlclient.Object, error) {
+	httpClient, err := rest.HTTPClientFor(apiExportCfg)
 	if err != nil {
-		return nil, fmt.Errorf("error watching for APIExport: %w", err)
-	}
-	if len(list.Items) > 0 && isAPIExportReady(&list.Items[0]) {
-		cfg = rest.CopyConfig(cfg)
-		// TODO: sharding support
-		//nolint:staticcheck // SA1019 VirtualWorkspaces is deprecated but not removed yet
-		cfg.Host = list.Items[0].Status.VirtualWorkspaces[0].URL
-		return cfg, nil
+		return nil, err
 	}
-
-	rw, err := retrywatch.NewRetryWatcher(list.ResourceVersion, watcher(apiExportClient.Watch).FilteredBy(selector))
+	discoveryClient, err := client.NewClusterAwareDiscovery(apiExportCfg, httpClient)
 	if err != nil {
-		return nil, fmt.Errorf("error creating retry watcher for APIExport: %w", err)
+		return nil, err
 	}
-	defer rw.Stop()
-
-	logger.Info("Watching for APIExport to become ready", "name", apiExportName)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case e := <-rw.ResultChan():
-			switch e.Type {
-			case watch.Error:
-				return nil, fmt.Errorf("error watching for APIExport: %w", apierrors.FromObject(e.Object))
-
-			case watch.Added, watch.Modified:
-				apiExport, ok := e.Object.(*apisv1alpha1.APIExport)
-				if !ok {
-					return nil, fmt.Errorf("unexpected event object: %v", e.Object)
-				}
-				if !isAPIExportReady(apiExport) {
-					continue
-				}
-				cfg = rest.CopyConfig(cfg)
-				// TODO: sharding support
-				//nolint:staticcheck // SA1019 VirtualWorkspaces is deprecated but not removed yet
-				cfg.Host = apiExport.Status.VirtualWorkspaces[0].URL
-				return cfg, nil
-			}
+	var absent []ctrlclient.Object
+	for _, object := range objects {
+		gvk, err := apiutil.GVKForObject(object, scheme)
+		if err != nil {
+			return nil, err
+		}
+		served, err := client.IsAPIResourceServed(discoveryClient, gvk.GroupVersion().String(), gvk.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("error discovering %s API: %w", gvk.GroupKind(), err)
+		}
+		if !served {
+			absent = append(absent, object)
 		}
 	}
-}
-
-func isAPIExportReady(apiExport *apisv1alpha1.APIExport) bool {
-	if !conditions.IsTrue(apiExport, apisv1alpha1.APIExportVirtualWorkspaceURLsReady) {
-		logger.Info("APIExport virtual workspace URLs are not ready", "APIExport", apiExport.Name)
-		return false
-	}
-
-	//nolint:staticcheck // SA1019 VirtualWorkspaces is deprecated but not removed yet
-	if len(apiExport.Status.VirtualWorkspaces) == 0 {
-		logger.Info("APIExport does not have any virtual workspace URLs", "APIExport", apiExport.Name)
-		return false
-	}
-
-	return true
+	return absent, nil
 }
 
 func kcpAPIsGroupPresent(discoveryClient discovery.ServerGroupsInterface) bool {
@@ -403,42 +523,9 @@ func kcpAPIsGroupPresent(discoveryClient discovery.ServerGroupsInterface) bool {
 	return false
 }
 
-// getOperatorImage returns the image currently used by the running operator if present (when running out of cluster, it may be absent).
-// nolint: unused
-func getOperatorImage(ctx context.Context, c ctrlclient.Reader) (string, error) {
-	ns := platform.GetOperatorNamespace()
-	name := platform.GetOperatorPodName()
-	if ns == "" || name == "" {
-		return "", nil
-	}
-
-	pod := corev1.Pod{}
-	if err := c.Get(ctx, ctrlclient.ObjectKey{Namespace: ns, Name: name}, &pod); err != nil && apierrors.IsNotFound(err) {
-		return "", nil
-	} else if err != nil {
-		return "", err
-	}
-	if len(pod.Spec.Containers) == 0 {
-		return "", fmt.Errorf("no containers found in operator pod")
-	}
-	return pod.Spec.Containers[0].Image, nil
-}
-
 func exitOnError(err error, msg string) {
 	if err != nil {
 		logger.Error(err, msg)
 		os.Exit(1)
 	}
 }
-
-type watcher func(ctx context.Context, obj ctrlclient.ObjectList, opts ...ctrlclient.ListOption) (watch.Interface, error)
-
-func (w watcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
-	return w(context.TODO(), &apisv1alpha1.APIExportList{}, &ctrlclient.ListOptions{Raw: &options})
-}
-
-func (w watcher) FilteredBy(selector fields.Selector) watcher {
-	return func(ctx context.Context, obj ctrlclient.ObjectList, opts ...ctrlclient.ListOption) (watch.Interface, error) {
-		return w(ctx, obj, append(opts, ctrlclient.MatchingFieldsSelector{Selector: selector})...)
-	}
-}
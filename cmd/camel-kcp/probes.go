@@ -0,0 +1,90 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// serveMetrics serves the metrics registered in the controller-runtime global registry,
+// which is shared by all the managers.
+func serveMetrics(ctx context.Context, addr string) func() error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.HTTPErrorOnError,
+	}))
+	return serve(ctx, "metrics", addr, mux)
+}
+
+// serveHealthProbes serves the liveness and readiness probes for all the managers.
+// The leadership check is served on the /readyz/leader endpoint, and is excluded from /readyz,
+// so that standby replicas are ready, while the leadership can still be probed.
+func serveHealthProbes(ctx context.Context, addr string, readyzChecks map[string]healthz.Checker, leaderCheck healthz.Checker) func() error {
+	mux := http.NewServeMux()
+	healthzHandler := &healthz.Handler{Checks: map[string]healthz.Checker{"healthz": healthz.Ping}}
+	readyzHandler := &healthz.Handler{Checks: map[string]healthz.Checker{"readyz": healthz.Ping}}
+	for name, check := range readyzChecks {
+		readyzHandler.Checks[name] = check
+	}
+	mux.Handle("/healthz", http.StripPrefix("/healthz", healthzHandler))
+	mux.Handle("/healthz/", http.StripPrefix("/healthz", healthzHandler))
+	mux.Handle("/readyz", http.StripPrefix("/readyz", readyzHandler))
+	mux.Handle("/readyz/", http.StripPrefix("/readyz", readyzHandler))
+	mux.Handle("/readyz/leader", http.StripPrefix("/readyz", &healthz.Handler{Checks: map[string]healthz.Checker{"leader": leaderCheck}}))
+	return serve(ctx, "health probes", addr, mux)
+}
+
+func serve(ctx context.Context, name string, addr string, handler http.Handler) func() error {
+	return func() error {
+		if addr == "" || addr == "0" {
+			logger.Info("Serving is disabled", "name", name)
+			return nil
+		}
+
+		server := &http.Server{
+			Addr:              addr,
+			Handler:           handler,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				logger.Error(err, "error shutting down server", "name", name)
+			}
+		}()
+
+		logger.Info("Starting server", "name", name, "address", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/apache/camel-kcp/pkg/client"
+)
+
+const reachabilityTimeout = 5 * time.Second
+
+// apiExportReadiness tracks the virtual workspaces of an APIExport, the manager runs against,
+// and provides the readiness checks reflecting their state.
+type apiExportReadiness struct {
+	name      string
+	lock      sync.RWMutex
+	endpoints map[string]*endpointReadiness
+}
+
+type endpointReadiness struct {
+	// The discovery client for all the logical clusters of the virtual workspace
+	discovery *client.ClusterAwareDiscovery
+	synced    bool
+}
+
+func newAPIExportReadiness(name string) *apiExportReadiness {
+	return &apiExportReadiness{
+		name:      name,
+		endpoints: make(map[string]*endpointReadiness),
+	}
+}
+
+// Checks returns the readiness checks, keyed by name, checking that:
+//   - at least one virtual workspace URL is resolved for the APIExport,
+//   - the caches of the manager are synced, for all the virtual workspaces,
+//   - all the virtual workspaces are reachable.
+func (r *apiExportReadiness) Checks() map[string]healthz.Checker {
+	return map[string]healthz.Checker{
+		r.name + "-virtual-workspace":           r.checkResolved,
+		r.name + "-caches-synced":               r.checkSynced,
+		r.name + "-virtual-workspace-reachable": r.checkReachable,
+	}
+}
+
+// track wraps the given run, so that the virtual workspace is tracked while the run is running.
+func (r *apiExportReadiness) track(run runFunc) runFunc {
+	return func(ctx context.Context, cfg *rest.Config) error {
+		httpClient, err := rest.HTTPClientFor(cfg)
+		if err != nil {
+			return err
+		}
+		discoveryClient, err := client.NewClusterAwareDiscovery(cfg, httpClient)
+		if err != nil {
+			return err
+		}
+
+		r.lock.Lock()
+		r.endpoints[cfg.Host] = &endpointReadiness{discovery: discoveryClient}
+		r.lock.Unlock()
+
+		defer func() {
+			r.lock.Lock()
+			delete(r.endpoints, cfg.Host)
+			r.lock.Unlock()
+		}()
+
+		return run(ctx, cfg)
+	}
+}
+
+// waitForCacheSync adds a runnable to the manager, that marks the virtual workspace as synced,
+// once the caches of the manager are synced.
+func (r *apiExportReadiness) waitForCacheSync(mgr manager.Manager, url string) error {
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if !mgr.GetCache().WaitForCacheSync(ctx) {
+			return nil
+		}
+		logger.Info("Caches synced", "api-export", r.name, "url", url)
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if endpoint, ok := r.endpoints[url]; ok {
+			endpoint.synced = true
+		}
+		return nil
+	}))
+}
+
+func (r *apiExportReadiness) checkResolved(_ *http.Request) error {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if len(r.endpoints) == 0 {
+		return fmt.Errorf("no virtual workspace resolved for APIExport %s", r.name)
+	}
+	return nil
+}
+
+func (r *apiExportReadiness) checkSynced(_ *http.Request) error {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var urls []string
+	for url, endpoint := range r.endpoints {
+		if !endpoint.synced {
+			urls = append(urls, url)
+		}
+	}
+	if len(urls) > 0 {
+		sort.Strings(urls)
+		return fmt.Errorf("caches not synced for virtual workspaces %v", urls)
+	}
+	return nil
+}
+
+func (r *apiExportReadiness) checkReachable(req *http.Request) error {
+	r.lock.RLock()
+	endpoints := make(map[string]*endpointReadiness, len(r.endpoints))
+	for url, endpoint := range r.endpoints {
+		endpoints[url] = endpoint
+	}
+	r.lock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(req.Context(), reachabilityTimeout)
+	defer cancel()
+
+	var errs []error
+	for url, endpoint := range endpoints {
+		if err := endpoint.discovery.RESTClient().Get().AbsPath("/api").Do(ctx).Error(); err != nil {
+			errs = append(errs, fmt.Errorf("virtual workspace %s is not reachable: %w", url, err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
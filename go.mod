@@ -6,6 +6,8 @@ require (
 	github.com/apache/camel-k v1.12.0
 	github.com/apache/camel-k/pkg/apis/camel v1.12.0
 	github.com/apache/camel-k/pkg/client/camel v1.12.0
+	github.com/evanphx/json-patch v5.6.0+incompatible
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/kcp-dev/apimachinery/v2 v2.0.0-alpha.0.0.20230113171111-a259d60637ec
 	github.com/kcp-dev/client-go v0.0.0-20230126185145-aeff170a288b
 	github.com/kcp-dev/kcp v0.11.0
@@ -13,6 +15,7 @@ require (
 	github.com/kcp-dev/kcp/pkg/client v0.0.0-00010101000000-000000000000
 	github.com/kcp-dev/logicalcluster/v3 v3.0.4
 	github.com/onsi/gomega v1.22.1
+	github.com/prometheus/client_golang v1.14.0
 	go.uber.org/automaxprocs v1.5.1
 	go.uber.org/zap v1.24.0
 	golang.org/x/sync v0.1.0
@@ -23,6 +26,7 @@ require (
 	k8s.io/component-base v0.25.2
 	k8s.io/klog/v2 v2.80.0
 	k8s.io/utils v0.0.0-20220823124924-e9cbc92d1a73
+	knative.dev/eventing v0.35.3
 	knative.dev/serving v0.35.3
 	sigs.k8s.io/controller-runtime v0.13.1
 	sigs.k8s.io/yaml v1.3.0
@@ -59,10 +63,8 @@ require (
 	github.com/docker/docker v23.0.1+incompatible // indirect
 	github.com/docker/docker-credential-helpers v0.7.0 // indirect
 	github.com/emicklei/go-restful/v3 v3.8.0 // indirect
-	github.com/evanphx/json-patch v5.6.0+incompatible // indirect
 	github.com/evanphx/json-patch/v5 v5.6.0 // indirect
 	github.com/fatih/structs v1.1.0 // indirect
-	github.com/fsnotify/fsnotify v1.6.0 // indirect
 	github.com/go-errors/errors v1.0.1 // indirect
 	github.com/go-kit/log v0.2.1 // indirect
 	github.com/go-logfmt/logfmt v0.5.1 // indirect
@@ -114,7 +116,6 @@ require (
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring v0.60.0 // indirect
-	github.com/prometheus/client_golang v1.14.0 // indirect
 	github.com/prometheus/client_model v0.3.0 // indirect
 	github.com/prometheus/common v0.39.0 // indirect
 	github.com/prometheus/procfs v0.8.0 // indirect
@@ -156,7 +157,6 @@ require (
 	k8s.io/apiserver v0.25.2 // indirect
 	k8s.io/kube-openapi v0.0.0-20220803162953-67bda5d908f1 // indirect
 	k8s.io/kubectl v0.25.2 // indirect
-	knative.dev/eventing v0.35.3 // indirect
 	knative.dev/networking v0.0.0-20221012062251-58f3e6239b4f // indirect
 	knative.dev/pkg v0.0.0-20221123011842-b78020c16606 // indirect
 	sigs.k8s.io/json v0.0.0-20220713155537-f223a00ba0e2 // indirect
@@ -18,17 +18,21 @@ limitations under the License.
 package client
 
 import (
+	"context"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/scale"
 
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/kcp"
 
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned"
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
 
@@ -40,13 +44,15 @@ import (
 
 type client struct {
 	ctrl.Client
-	discovery discovery.DiscoveryInterface
+	discovery *ClusterAwareDiscovery
+	bound     *BoundDiscovery
 	kubernetes.Interface
 	kcp    kcpclientset.Interface
 	camel  camel.Interface
 	scheme *runtime.Scheme
 	config *rest.Config
 	rest   rest.Interface
+	cache  *DiscoveryCache
 }
 
 func NewClient(cfg *rest.Config, scheme *runtime.Scheme, c ctrl.Client) (Client, error) {
@@ -54,7 +60,7 @@ func NewClient(cfg *rest.Config, scheme *runtime.Scheme, c ctrl.Client) (Client,
 	if err != nil {
 		return nil, err
 	}
-	discoveryClient, err := NewClusterAwareDiscovery(cfg)
+	discoveryClient, err := NewClusterAwareDiscovery(cfg, httpClient)
 	if err != nil {
 		return nil, err
 	}
@@ -78,19 +84,52 @@ func NewClient(cfg *rest.Config, scheme *runtime.Scheme, c ctrl.Client) (Client,
 	return &client{
 		Client:    c,
 		discovery: discoveryClient,
+		bound:     NewBoundDiscovery(discoveryClient, boundClusters(c)),
 		Interface: kubeClient,
 		kcp:       kcpClient,
 		camel:     camelClient,
 		scheme:    scheme,
 		config:    cfg,
 		rest:      restClient,
+		cache:     NewDiscoveryCache(discoveryClient),
 	}, nil
 }
 
 var _ Client = &client{}
 
+// Discovery returns the discovery client for all the logical clusters, i.e., the intersection of the bound APIs,
+// so that the feature detection that is not scoped to a logical cluster, e.g., that of Camel K, does not detect
+// the APIs that are not bound in some consumer workspaces.
+// ClusterDiscovery must be used to detect the APIs bound in a particular consumer workspace.
 func (c *client) Discovery() discovery.DiscoveryInterface {
-	return c.discovery
+	return c.bound
+}
+
+func (c *client) ClusterDiscovery(ctx context.Context) (discovery.DiscoveryInterface, error) {
+	return c.discovery.WithContext(ctx)
+}
+
+func (c *client) InvalidateDiscovery() {
+	c.cache.Invalidate()
+	c.bound.Invalidate()
+}
+
+// boundClusters returns the function listing the logical clusters, where the APIExport of the virtual workspace
+// is bound, from the APIBindings served by the virtual workspace.
+func boundClusters(r ctrl.Reader) func() ([]logicalcluster.Name, error) {
+	return func() ([]logicalcluster.Name, error) {
+		bindings := &apisv1alpha1.APIBindingList{}
+		if err := r.List(context.Background(), bindings); err != nil {
+			return nil, err
+		}
+		var clusters []logicalcluster.Name
+		for i := range bindings.Items {
+			if binding := &bindings.Items[i]; binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound {
+				clusters = append(clusters, logicalcluster.From(binding))
+			}
+		}
+		return clusters, nil
+	}
 }
 
 func (c *client) KcpSchedulingV1alpha1() schedulingv1alpha1.SchedulingV1alpha1Interface {
@@ -124,12 +163,6 @@ func (c *client) ServerOrClientSideApplier() camelclient.ServerOrClientSideAppli
 }
 
 func (c *client) ScalesClient() (scale.ScalesGetter, error) {
-	// Polymorphic scale client
-	groupResources, err := restmapper.GetAPIGroupResources(c.Discovery())
-	if err != nil {
-		return nil, err
-	}
-	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
-	resolver := scale.NewDiscoveryScaleKindResolver(c.Discovery())
-	return scale.New(c.rest, mapper, dynamic.LegacyAPIPathResolverFunc, resolver), nil
+	// Polymorphic scale client, backed by the discovery cache shared by all the calls
+	return scale.New(c.rest, c.cache.RESTMapper(), dynamic.LegacyAPIPathResolverFunc, c.cache.ScaleKindResolver()), nil
 }
@@ -0,0 +1,266 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	goerrors "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+const (
+	// clusterDiscoveryCacheSize is the maximum number of logical clusters, whose discovery information is cached.
+	clusterDiscoveryCacheSize = 1000
+	// clusterDiscoveryCacheTTL is the duration the discovery information of a logical cluster is cached for.
+	clusterDiscoveryCacheTTL = time.Minute
+)
+
+// ClusterAwareDiscovery is a discovery client for the APIExport virtual workspace API server.
+// It returns the union of the APIs bound in all the logical clusters, unless it's scoped to
+// a logical cluster, in which case it returns the APIs bound in that logical cluster only.
+type ClusterAwareDiscovery struct {
+	discovery.DiscoveryInterface
+	config     *rest.Config
+	httpClient *http.Client
+	clusters   *cache.LRUExpireCache
+}
+
+// NewClusterAwareDiscovery returns a ClusterAwareDiscovery for the APIExport virtual workspace API server,
+// with the given configuration and HTTP client.
+func NewClusterAwareDiscovery(config *rest.Config, httpClient *http.Client) (*ClusterAwareDiscovery, error) {
+	wildcard, err := discovery.NewDiscoveryClientForConfigAndClient(clusterConfig(config, logicalcluster.Wildcard), httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &ClusterAwareDiscovery{
+		DiscoveryInterface: wildcard,
+		config:             config,
+		httpClient:         httpClient,
+		clusters:           cache.NewLRUExpireCache(clusterDiscoveryCacheSize),
+	}, nil
+}
+
+// Cluster returns a discovery client scoped to the given logical cluster.
+// The discovery information is cached for a short period of time.
+func (d *ClusterAwareDiscovery) Cluster(cluster logicalcluster.Name) (discovery.DiscoveryInterface, error) {
+	if client, ok := d.clusters.Get(cluster); ok {
+		return client.(discovery.DiscoveryInterface), nil
+	}
+	client, err := discovery.NewDiscoveryClientForConfigAndClient(clusterConfig(d.config, cluster.Path()), d.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	cached := memory.NewMemCacheClient(client)
+	d.clusters.Add(cluster, cached, clusterDiscoveryCacheTTL)
+	return cached, nil
+}
+
+// WithContext returns a discovery client scoped to the logical cluster of the given context,
+// or the discovery client for all the logical clusters, if the context has no logical cluster.
+func (d *ClusterAwareDiscovery) WithContext(ctx context.Context) (discovery.DiscoveryInterface, error) {
+	if cluster, ok := kontext.ClusterFrom(ctx); ok && !cluster.Empty() {
+		return d.Cluster(cluster)
+	}
+	return d.DiscoveryInterface, nil
+}
+
+func clusterConfig(config *rest.Config, path logicalcluster.Path) *rest.Config {
+	c := rest.CopyConfig(config)
+	c.Host += path.RequestPath()
+	return c
+}
+
+// BoundDiscovery is a discovery client for the APIExport virtual workspace API server, that returns the APIs
+// bound in each of the logical clusters the APIExport is bound into, i.e., the intersection of the bound APIs.
+// It's meant for the feature detection that has no logical cluster to scope the discovery to, so that an API
+// is not detected for the consumer workspaces where it's not bound. Note an API that's not bound in one of the
+// consumer workspaces is therefore not detected for any of them.
+// The intersection is cached, as computing it requires the discovery of all the logical clusters, until it
+// expires, or it's invalidated, e.g., when the APIExport is bound into, or unbound from, a consumer workspace.
+type BoundDiscovery struct {
+	discovery.DiscoveryInterface
+	discovery *ClusterAwareDiscovery
+	clusters  func() ([]logicalcluster.Name, error)
+
+	lock      sync.Mutex
+	groups    *metav1.APIGroupList
+	resources map[string]*metav1.APIResourceList
+	cached    time.Time
+	now       func() time.Time
+}
+
+// NewBoundDiscovery returns a BoundDiscovery, for the logical clusters returned by the given function.
+func NewBoundDiscovery(d *ClusterAwareDiscovery, clusters func() ([]logicalcluster.Name, error)) *BoundDiscovery {
+	return &BoundDiscovery{
+		DiscoveryInterface: d,
+		discovery:          d,
+		clusters:           clusters,
+		resources:          map[string]*metav1.APIResourceList{},
+		now:                time.Now,
+	}
+}
+
+// Invalidate invalidates the cached intersection, so that it's computed again on the next call.
+func (d *BoundDiscovery) Invalidate() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.reset()
+}
+
+// reset resets the cache. It must be called with the lock held.
+func (d *BoundDiscovery) reset() {
+	d.groups = nil
+	d.resources = map[string]*metav1.APIResourceList{}
+	d.cached = d.now()
+}
+
+// expire resets the cache if it has expired. It must be called with the lock held.
+func (d *BoundDiscovery) expire() {
+	if d.now().Sub(d.cached) > discoveryCacheTTL {
+		d.reset()
+	}
+}
+
+func (d *BoundDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.expire()
+	if d.groups != nil {
+		return d.groups.DeepCopy(), nil
+	}
+
+	clusters, err := d.clusters()
+	if err != nil {
+		return nil, err
+	}
+	groups := &metav1.APIGroupList{}
+	for i, cluster := range clusters {
+		c, err := d.discovery.Cluster(cluster)
+		if err != nil {
+			return nil, err
+		}
+		list, err := c.ServerGroups()
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			groups = list.DeepCopy()
+		} else {
+			groups.Groups = intersect(groups.Groups, list.Groups, func(g metav1.APIGroup) string { return g.Name })
+		}
+	}
+	d.groups = groups
+	return groups.DeepCopy(), nil
+}
+
+func (d *BoundDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	// Mimic the discovery client, that returns a not found error for the group versions that are not served
+	notFound := errors.NewNotFound(schema.GroupResource{Group: groupVersion}, "")
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.expire()
+	if resources, ok := d.resources[groupVersion]; ok {
+		if resources == nil {
+			return nil, notFound
+		}
+		return resources.DeepCopy(), nil
+	}
+
+	resources, err := d.serverResourcesForGroupVersion(groupVersion)
+	if errors.IsNotFound(err) {
+		d.resources[groupVersion] = nil
+		return nil, notFound
+	} else if err != nil {
+		return nil, err
+	}
+	d.resources[groupVersion] = resources
+	return resources.DeepCopy(), nil
+}
+
+func (d *BoundDiscovery) serverResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	clusters, err := d.clusters()
+	if err != nil {
+		return nil, err
+	}
+	notFound := errors.NewNotFound(schema.GroupResource{Group: groupVersion}, "")
+	if len(clusters) == 0 {
+		return nil, notFound
+	}
+	var resources *metav1.APIResourceList
+	for _, cluster := range clusters {
+		c, err := d.discovery.Cluster(cluster)
+		if err != nil {
+			return nil, err
+		}
+		list, err := c.ServerResourcesForGroupVersion(groupVersion)
+		if goerrors.Is(err, memory.ErrCacheNotFound) {
+			return nil, notFound
+		} else if err != nil {
+			return nil, err
+		}
+		if resources == nil {
+			resources = list.DeepCopy()
+		} else {
+			resources.APIResources = intersect(resources.APIResources, list.APIResources, func(r metav1.APIResource) string { return r.Name })
+		}
+	}
+	return resources, nil
+}
+
+func (d *BoundDiscovery) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	return discovery.ServerGroupsAndResources(d)
+}
+
+func (d *BoundDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	return discovery.ServerPreferredResources(d)
+}
+
+func (d *BoundDiscovery) ServerPreferredNamespacedResources() ([]*metav1.APIResourceList, error) {
+	return discovery.ServerPreferredNamespacedResources(d)
+}
+
+// intersect returns the items that have a key in the other items.
+func intersect[T any](items, others []T, key func(T) string) []T {
+	keys := sets.NewString()
+	for _, other := range others {
+		keys.Insert(key(other))
+	}
+	var result []T
+	for _, item := range items {
+		if keys.Has(key(item)) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,235 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+// newTestDiscoveryServer returns a server for the APIExport virtual workspace discovery, where Knative Serving
+// is only bound in the "knative" logical cluster, while Camel K is bound in all the logical clusters.
+func newTestDiscoveryServer(t *testing.T) *httptest.Server {
+	camelK := metav1.APIGroup{
+		Name:             "camel.apache.org",
+		Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "camel.apache.org/v1", Version: "v1"}},
+		PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "camel.apache.org/v1", Version: "v1"},
+	}
+	knative := metav1.APIGroup{
+		Name:             "serving.knative.dev",
+		Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "serving.knative.dev/v1", Version: "v1"}},
+		PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "serving.knative.dev/v1", Version: "v1"},
+	}
+
+	responses := map[string]interface{}{
+		"/api":  &metav1.APIVersions{},
+		"/apis": &metav1.APIGroupList{Groups: []metav1.APIGroup{camelK, knative}},
+		"/apis/camel.apache.org/v1": &metav1.APIResourceList{
+			GroupVersion: "camel.apache.org/v1",
+			APIResources: []metav1.APIResource{{Name: "integrations", Kind: "Integration", Namespaced: true}},
+		},
+		"/apis/serving.knative.dev/v1": &metav1.APIResourceList{
+			GroupVersion: "serving.knative.dev/v1",
+			APIResources: []metav1.APIResource{{Name: "services", Kind: "Service", Namespaced: true}},
+		},
+	}
+	bound := map[string][]metav1.APIGroup{
+		"*":       {camelK, knative},
+		"knative": {camelK, knative},
+		"camel-k": {camelK},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var cluster, path string
+		for c := range bound {
+			if prefix := logicalcluster.NewPath(c).RequestPath(); strings.HasPrefix(r.URL.Path, prefix+"/") {
+				cluster, path = c, strings.TrimPrefix(r.URL.Path, prefix)
+			}
+		}
+		response, ok := responses[path]
+		if !ok || cluster == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if path == "/apis" {
+			response = &metav1.APIGroupList{Groups: bound[cluster]}
+		} else if list, ok := response.(*metav1.APIResourceList); ok {
+			found := false
+			for _, group := range bound[cluster] {
+				found = found || group.PreferredVersion.GroupVersion == list.GroupVersion
+			}
+			if !found {
+				http.NotFound(w, r)
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func TestClusterAwareDiscovery(t *testing.T) {
+	g := NewWithT(t)
+
+	server := newTestDiscoveryServer(t)
+	defer server.Close()
+
+	d, err := NewClusterAwareDiscovery(&rest.Config{Host: server.URL}, server.Client())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// Without a logical cluster in the context, the discovery returns the union of the bound APIs
+	all, err := d.WithContext(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	resources, err := all.ServerResourcesForGroupVersion("serving.knative.dev/v1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resources.APIResources).To(HaveLen(1))
+
+	knative, err := d.WithContext(kontext.WithCluster(context.Background(), "knative"))
+	g.Expect(err).NotTo(HaveOccurred())
+	resources, err = knative.ServerResourcesForGroupVersion("serving.knative.dev/v1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resources.APIResources).To(HaveLen(1))
+
+	camelK, err := d.WithContext(kontext.WithCluster(context.Background(), "camel-k"))
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = camelK.ServerResourcesForGroupVersion("serving.knative.dev/v1")
+	g.Expect(err).To(MatchError(memory.ErrCacheNotFound))
+	resources, err = camelK.ServerResourcesForGroupVersion("camel.apache.org/v1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resources.APIResources).To(HaveLen(1))
+
+	// The discovery client of a logical cluster is cached
+	cached, err := d.Cluster("camel-k")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cached).To(BeIdenticalTo(camelK))
+}
+
+func TestBoundDiscovery(t *testing.T) {
+	server := newTestDiscoveryServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		clusters []logicalcluster.Name
+		groups   []string
+		knative  bool
+	}{
+		{
+			name:     "Knative Serving bound in all the logical clusters",
+			clusters: []logicalcluster.Name{"knative"},
+			groups:   []string{"camel.apache.org", "serving.knative.dev"},
+			knative:  true,
+		},
+		{
+			name:     "Knative Serving bound in some logical clusters",
+			clusters: []logicalcluster.Name{"knative", "camel-k"},
+			groups:   []string{"camel.apache.org"},
+			knative:  false,
+		},
+		{
+			name:     "no bound logical clusters",
+			clusters: nil,
+			groups:   nil,
+			knative:  false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c, err := NewClusterAwareDiscovery(&rest.Config{Host: server.URL}, server.Client())
+			g.Expect(err).NotTo(HaveOccurred())
+			d := NewBoundDiscovery(c, func() ([]logicalcluster.Name, error) {
+				return test.clusters, nil
+			})
+
+			groups, err := d.ServerGroups()
+			g.Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, group := range groups.Groups {
+				names = append(names, group.Name)
+			}
+			g.Expect(names).To(Equal(test.groups))
+
+			_, err = d.ServerResourcesForGroupVersion("serving.knative.dev/v1")
+			if test.knative {
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			}
+
+			served, err := IsAPIResourceServed(d, "serving.knative.dev/v1", "Service")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(served).To(Equal(test.knative))
+		})
+	}
+}
+
+func TestBoundDiscoveryCache(t *testing.T) {
+	g := NewWithT(t)
+
+	server := newTestDiscoveryServer(t)
+	defer server.Close()
+
+	c, err := NewClusterAwareDiscovery(&rest.Config{Host: server.URL}, server.Client())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	calls := 0
+	clusters := []logicalcluster.Name{"knative"}
+	d := NewBoundDiscovery(c, func() ([]logicalcluster.Name, error) {
+		calls++
+		return clusters, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		served, err := IsAPIResourceServed(d, "serving.knative.dev/v1", "Service")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(served).To(BeTrue())
+	}
+	g.Expect(calls).To(Equal(1))
+
+	// The APIExport is bound into a logical cluster, where Knative Serving is not bound
+	clusters = []logicalcluster.Name{"knative", "camel-k"}
+	d.Invalidate()
+
+	for i := 0; i < 2; i++ {
+		served, err := IsAPIResourceServed(d, "serving.knative.dev/v1", "Service")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(served).To(BeFalse())
+	}
+	g.Expect(calls).To(Equal(2))
+}
@@ -0,0 +1,238 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/scale"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// discoveryCacheTTL is the duration after which the discovery cache is refreshed.
+	discoveryCacheTTL = 10 * time.Minute
+	// discoveryCacheMinRefreshInterval is the minimum duration between two refreshes of the discovery cache,
+	// triggered by lookups of unknown kinds or resources.
+	discoveryCacheMinRefreshInterval = 30 * time.Second
+)
+
+const (
+	refreshReasonExpired     = "expired"
+	refreshReasonInvalidated = "invalidated"
+	refreshReasonNoMatch     = "no_match"
+)
+
+var (
+	discoveryCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "camel_kcp_discovery_cache_lookups_total",
+			Help: "Total number of REST mapper and scale kind resolver lookups, by whether they were served from the discovery cache.",
+		},
+		[]string{"result"},
+	)
+	discoveryCacheRefreshes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "camel_kcp_discovery_cache_refreshes_total",
+			Help: "Total number of discovery cache refreshes, by reason.",
+		},
+		[]string{"reason"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(discoveryCacheLookups, discoveryCacheRefreshes)
+}
+
+// DiscoveryCache caches the discovery information, and provides the REST mapper and the scale kind resolver
+// backed by it, so that discovery is not performed across all the logical clusters for every scale operation.
+// The cache is refreshed when it expires, when it's invalidated, or when a lookup does not match any kind or
+// resource, at most once per minimum refresh interval.
+type DiscoveryCache struct {
+	discovery discovery.CachedDiscoveryInterface
+	mapper    *restmapper.DeferredDiscoveryRESTMapper
+	resolver  scale.ScaleKindResolver
+
+	lock      sync.Mutex
+	refreshed time.Time
+	now       func() time.Time
+}
+
+// NewDiscoveryCache returns a DiscoveryCache for the given discovery client.
+func NewDiscoveryCache(client discovery.DiscoveryInterface) *DiscoveryCache {
+	cached := memory.NewMemCacheClient(client)
+	return &DiscoveryCache{
+		discovery: cached,
+		mapper:    restmapper.NewDeferredDiscoveryRESTMapper(cached),
+		resolver:  scale.NewDiscoveryScaleKindResolver(cached),
+		refreshed: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// RESTMapper returns the REST mapper backed by the cache.
+func (c *DiscoveryCache) RESTMapper() meta.RESTMapper {
+	return &cachedRESTMapper{cache: c}
+}
+
+// ScaleKindResolver returns the scale kind resolver backed by the cache.
+func (c *DiscoveryCache) ScaleKindResolver() scale.ScaleKindResolver {
+	return &cachedScaleKindResolver{cache: c}
+}
+
+// Invalidate invalidates the cache, so that it's refreshed on the next lookup.
+// It's called when the APIExport is bound into, or unbound from, a consumer workspace.
+func (c *DiscoveryCache) Invalidate() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.refresh(refreshReasonInvalidated)
+}
+
+// refresh resets the cache. It must be called with the lock held.
+func (c *DiscoveryCache) refresh(reason string) {
+	c.mapper.Reset()
+	c.refreshed = c.now()
+	discoveryCacheRefreshes.WithLabelValues(reason).Inc()
+}
+
+// lookup calls the given lookup function, and retries it once the cache is refreshed,
+// if it fails with a retryable error, and the cache has not been refreshed recently.
+func (c *DiscoveryCache) lookup(f func() error, retryable func(error) bool) error {
+	c.lock.Lock()
+	if c.now().Sub(c.refreshed) > discoveryCacheTTL {
+		c.refresh(refreshReasonExpired)
+	}
+	c.lock.Unlock()
+
+	// The lookup is served from the cache if it's already populated
+	hit := c.discovery.Fresh()
+
+	err := f()
+	if err != nil && retryable(err) {
+		c.lock.Lock()
+		retry := c.now().Sub(c.refreshed) > discoveryCacheMinRefreshInterval
+		if retry {
+			c.refresh(refreshReasonNoMatch)
+		}
+		c.lock.Unlock()
+		if retry {
+			hit = false
+			err = f()
+		}
+	}
+
+	if hit {
+		discoveryCacheLookups.WithLabelValues("hit").Inc()
+	} else {
+		discoveryCacheLookups.WithLabelValues("miss").Inc()
+	}
+
+	return err
+}
+
+func isNoMatchError(err error) bool {
+	return meta.IsNoMatchError(err) || discovery.IsGroupDiscoveryFailedError(err)
+}
+
+type cachedRESTMapper struct {
+	cache *DiscoveryCache
+}
+
+var _ meta.RESTMapper = &cachedRESTMapper{}
+
+func (m *cachedRESTMapper) KindFor(resource schema.GroupVersionResource) (gvk schema.GroupVersionKind, err error) {
+	err = m.cache.lookup(func() (err error) {
+		gvk, err = m.cache.mapper.KindFor(resource)
+		return
+	}, isNoMatchError)
+	return
+}
+
+func (m *cachedRESTMapper) KindsFor(resource schema.GroupVersionResource) (gvks []schema.GroupVersionKind, err error) {
+	err = m.cache.lookup(func() (err error) {
+		gvks, err = m.cache.mapper.KindsFor(resource)
+		return
+	}, isNoMatchError)
+	return
+}
+
+func (m *cachedRESTMapper) ResourceFor(input schema.GroupVersionResource) (gvr schema.GroupVersionResource, err error) {
+	err = m.cache.lookup(func() (err error) {
+		gvr, err = m.cache.mapper.ResourceFor(input)
+		return
+	}, isNoMatchError)
+	return
+}
+
+func (m *cachedRESTMapper) ResourcesFor(input schema.GroupVersionResource) (gvrs []schema.GroupVersionResource, err error) {
+	err = m.cache.lookup(func() (err error) {
+		gvrs, err = m.cache.mapper.ResourcesFor(input)
+		return
+	}, isNoMatchError)
+	return
+}
+
+func (m *cachedRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (mapping *meta.RESTMapping, err error) {
+	err = m.cache.lookup(func() (err error) {
+		mapping, err = m.cache.mapper.RESTMapping(gk, versions...)
+		return
+	}, isNoMatchError)
+	return
+}
+
+func (m *cachedRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) (mappings []*meta.RESTMapping, err error) {
+	err = m.cache.lookup(func() (err error) {
+		mappings, err = m.cache.mapper.RESTMappings(gk, versions...)
+		return
+	}, isNoMatchError)
+	return
+}
+
+func (m *cachedRESTMapper) ResourceSingularizer(resource string) (singular string, err error) {
+	err = m.cache.lookup(func() (err error) {
+		singular, err = m.cache.mapper.ResourceSingularizer(resource)
+		return
+	}, isNoMatchError)
+	return
+}
+
+type cachedScaleKindResolver struct {
+	cache *DiscoveryCache
+}
+
+var _ scale.ScaleKindResolver = &cachedScaleKindResolver{}
+
+func (r *cachedScaleKindResolver) ScaleForResource(resource schema.GroupVersionResource) (gvk schema.GroupVersionKind, err error) {
+	err = r.cache.lookup(func() (err error) {
+		gvk, err = r.cache.resolver.ScaleForResource(resource)
+		return
+	}, func(error) bool {
+		// The resolver errors do not tell whether the resource is missing from the cache
+		return true
+	})
+	return
+}
@@ -0,0 +1,168 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var (
+	integrationsGroupKind   = schema.GroupKind{Group: "camel.apache.org", Kind: "Integration"}
+	kameletBindingGroupKind = schema.GroupKind{Group: "camel.apache.org", Kind: "KameletBinding"}
+)
+
+func newFakeDiscovery() *fakediscovery.FakeDiscovery {
+	return &fakediscovery.FakeDiscovery{
+		Fake: &clienttesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "camel.apache.org/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "integrations", Kind: "Integration", Namespaced: true},
+						{Name: "integrations/scale", Kind: "Scale", Group: "autoscaling", Version: "v1"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func addKameletBindings(discovery *fakediscovery.FakeDiscovery) {
+	discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{
+		GroupVersion: "camel.apache.org/v1alpha1",
+		APIResources: []metav1.APIResource{
+			{Name: "kameletbindings", Kind: "KameletBinding", Namespaced: true},
+		},
+	})
+}
+
+func newTestDiscoveryCache(discovery *fakediscovery.FakeDiscovery) (*DiscoveryCache, *time.Time) {
+	now := time.Now()
+	cache := NewDiscoveryCache(discovery)
+	cache.now = func() time.Time { return now }
+	cache.refreshed = now
+	return cache, &now
+}
+
+func TestDiscoveryCacheHit(t *testing.T) {
+	g := NewWithT(t)
+
+	cache, _ := newTestDiscoveryCache(newFakeDiscovery())
+	mapper := cache.RESTMapper()
+
+	hits := testutil.ToFloat64(discoveryCacheLookups.WithLabelValues("hit"))
+	misses := testutil.ToFloat64(discoveryCacheLookups.WithLabelValues("miss"))
+
+	mapping, err := mapper.RESTMapping(integrationsGroupKind)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mapping.Resource.Resource).To(Equal("integrations"))
+
+	_, err = mapper.RESTMapping(integrationsGroupKind)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(testutil.ToFloat64(discoveryCacheLookups.WithLabelValues("miss")) - misses).To(Equal(1.0))
+	g.Expect(testutil.ToFloat64(discoveryCacheLookups.WithLabelValues("hit")) - hits).To(Equal(1.0))
+}
+
+func TestDiscoveryCacheScaleKindResolver(t *testing.T) {
+	g := NewWithT(t)
+
+	cache, _ := newTestDiscoveryCache(newFakeDiscovery())
+
+	gvk, err := cache.ScaleKindResolver().ScaleForResource(schema.GroupVersionResource{
+		Group:    "camel.apache.org",
+		Version:  "v1",
+		Resource: "integrations",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gvk).To(Equal(schema.GroupVersionKind{Group: "autoscaling", Version: "v1", Kind: "Scale"}))
+}
+
+func TestDiscoveryCacheRefreshOnNoMatch(t *testing.T) {
+	g := NewWithT(t)
+
+	discovery := newFakeDiscovery()
+	cache, now := newTestDiscoveryCache(discovery)
+	mapper := cache.RESTMapper()
+
+	_, err := mapper.RESTMapping(integrationsGroupKind)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	addKameletBindings(discovery)
+	refreshes := testutil.ToFloat64(discoveryCacheRefreshes.WithLabelValues(refreshReasonNoMatch))
+
+	// The cache is not refreshed, as it's been refreshed recently
+	_, err = mapper.RESTMapping(kameletBindingGroupKind)
+	g.Expect(meta.IsNoMatchError(err)).To(BeTrue())
+	g.Expect(testutil.ToFloat64(discoveryCacheRefreshes.WithLabelValues(refreshReasonNoMatch))).To(Equal(refreshes))
+
+	*now = now.Add(discoveryCacheMinRefreshInterval + time.Second)
+
+	mapping, err := mapper.RESTMapping(kameletBindingGroupKind)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mapping.Resource.Resource).To(Equal("kameletbindings"))
+	g.Expect(testutil.ToFloat64(discoveryCacheRefreshes.WithLabelValues(refreshReasonNoMatch))).To(Equal(refreshes + 1))
+}
+
+func TestDiscoveryCacheInvalidate(t *testing.T) {
+	g := NewWithT(t)
+
+	discovery := newFakeDiscovery()
+	cache, _ := newTestDiscoveryCache(discovery)
+	mapper := cache.RESTMapper()
+
+	_, err := mapper.RESTMapping(integrationsGroupKind)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	addKameletBindings(discovery)
+	cache.Invalidate()
+
+	_, err = mapper.RESTMapping(kameletBindingGroupKind)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestDiscoveryCacheExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	discovery := newFakeDiscovery()
+	cache, now := newTestDiscoveryCache(discovery)
+	mapper := cache.RESTMapper()
+
+	_, err := mapper.KindFor(schema.GroupVersionResource{Group: "camel.apache.org", Resource: "integrations"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	addKameletBindings(discovery)
+	refreshes := testutil.ToFloat64(discoveryCacheRefreshes.WithLabelValues(refreshReasonExpired))
+	*now = now.Add(discoveryCacheTTL + time.Second)
+
+	gvk, err := mapper.KindFor(schema.GroupVersionResource{Group: "camel.apache.org", Resource: "kameletbindings"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(gvk.Kind).To(Equal("KameletBinding"))
+	g.Expect(testutil.ToFloat64(discoveryCacheRefreshes.WithLabelValues(refreshReasonExpired))).To(Equal(refreshes + 1))
+}
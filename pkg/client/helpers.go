@@ -18,20 +18,43 @@ limitations under the License.
 package client
 
 import (
+	"context"
+	goerrors "errors"
 	"net/http"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/scale"
 )
 
-// NewClusterAwareDiscovery returns a discovery.DiscoveryInterface that works with APIExport virtual workspace API server.
-func NewClusterAwareDiscovery(config *rest.Config) (discovery.DiscoveryInterface, error) {
-	c := rest.CopyConfig(config)
-	c.Host += "/clusters/*"
-	return discovery.NewDiscoveryClientForConfig(c)
+// IsAPIResourceInstalled returns whether the API resource of the given kind is bound
+// in the logical cluster of the context.
+func IsAPIResourceInstalled(ctx context.Context, c Client, groupVersion string, kind string) (bool, error) {
+	d, err := c.ClusterDiscovery(ctx)
+	if err != nil {
+		return false, err
+	}
+	return IsAPIResourceServed(d, groupVersion, kind)
+}
+
+// IsAPIResourceServed returns whether the API resource of the given kind is served by the discovery client.
+func IsAPIResourceServed(d discovery.DiscoveryInterface, groupVersion string, kind string) (bool, error) {
+	resources, err := d.ServerResourcesForGroupVersion(groupVersion)
+	if errors.IsNotFound(err) || goerrors.Is(err, memory.ErrCacheNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == kind {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 var scaleConverter = scale.NewScaleConverter()
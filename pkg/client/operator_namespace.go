@@ -0,0 +1,70 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+// OperatorNamespaceFunc returns the operator namespace of the logical cluster of the given context.
+type OperatorNamespaceFunc func(ctx context.Context) (string, error)
+
+type operatorNamespaceClient struct {
+	Client
+	operatorNamespace OperatorNamespaceFunc
+}
+
+// WithOperatorNamespace returns a client that lists the integration platforms of the operator namespace
+// of the logical cluster, when none exists in the requested namespace. Camel K falls back to the integration
+// platform of the operator namespace it reads from the environment, that cannot vary per logical cluster.
+func WithOperatorNamespace(c Client, operatorNamespace OperatorNamespaceFunc) Client {
+	return &operatorNamespaceClient{
+		Client:            c,
+		operatorNamespace: operatorNamespace,
+	}
+}
+
+func (c *operatorNamespaceClient) List(ctx context.Context, list ctrl.ObjectList, opts ...ctrl.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+
+	platforms, ok := list.(*v1.IntegrationPlatformList)
+	if !ok || len(platforms.Items) > 0 {
+		return nil
+	}
+	listOptions := &ctrl.ListOptions{}
+	listOptions.ApplyOptions(opts)
+	if listOptions.Namespace == "" {
+		return nil
+	}
+
+	namespace, err := c.operatorNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	if namespace == listOptions.Namespace {
+		return nil
+	}
+
+	return c.Client.List(ctx, list, append(opts, ctrl.InNamespace(namespace))...)
+}
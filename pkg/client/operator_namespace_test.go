@@ -0,0 +1,85 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func TestOperatorNamespaceFallback(t *testing.T) {
+	scheme := runtime.NewScheme()
+	NewWithT(t).Expect(v1.AddToScheme(scheme)).To(Succeed())
+
+	platform := func(namespace string) *v1.IntegrationPlatform {
+		return &v1.IntegrationPlatform{ObjectMeta: metav1.ObjectMeta{Name: "camel-k", Namespace: namespace}}
+	}
+	c := WithOperatorNamespace(&client{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(platform("my-camel-k"), platform("local")).Build(),
+	}, func(context.Context) (string, error) {
+		return "my-camel-k", nil
+	})
+
+	tests := []struct {
+		name       string
+		namespace  string
+		namespaces []string
+	}{
+		{
+			name:       "local platform",
+			namespace:  "local",
+			namespaces: []string{"local"},
+		},
+		{
+			name:       "operator namespace platform",
+			namespace:  "other",
+			namespaces: []string{"my-camel-k"},
+		},
+		{
+			name:       "all namespaces",
+			namespace:  "",
+			namespaces: []string{"local", "my-camel-k"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			list := &v1.IntegrationPlatformList{}
+			g.Expect(c.List(context.Background(), list, ctrl.InNamespace(test.namespace))).To(Succeed())
+
+			var namespaces []string
+			for _, ip := range list.Items {
+				namespaces = append(namespaces, ip.Namespace)
+			}
+			g.Expect(namespaces).To(ConsistOf(test.namespaces))
+		})
+	}
+}
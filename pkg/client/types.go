@@ -18,6 +18,10 @@ limitations under the License.
 package client
 
 import (
+	"context"
+
+	"k8s.io/client-go/discovery"
+
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
 
 	camel "github.com/apache/camel-k/pkg/client"
@@ -26,4 +30,10 @@ import (
 type Client interface {
 	camel.Client
 	KcpSchedulingV1alpha1() schedulingv1alpha1.SchedulingV1alpha1Interface
+	// ClusterDiscovery returns a discovery client scoped to the logical cluster of the context,
+	// so that the APIs bound in a particular consumer workspace can be detected.
+	ClusterDiscovery(ctx context.Context) (discovery.DiscoveryInterface, error)
+	// InvalidateDiscovery invalidates the cached discovery information, so that the APIs that are bound,
+	// or unbound, with the APIExport of the virtual workspace, in a consumer workspace, are discovered.
+	InvalidateDiscovery()
 }
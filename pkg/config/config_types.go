@@ -20,7 +20,9 @@ limitations under the License.
 package config
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	cfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
 
@@ -45,6 +47,67 @@ type ServiceConfiguration struct {
 type ServiceConfigurationSpec struct {
 	// The APIExports used to configure the controller managers.
 	APIExports APIExports `json:"apiExports,omitempty"`
+
+	// The namespace, in the service workspace, where the provisioning
+	// status of the consumer workspaces is aggregated.
+	// Defaults to camel-kcp.
+	// +optional
+	StatusNamespace string `json:"statusNamespace,omitempty"`
+
+	// The container image of the operator, that's used, e.g., by the Camel K builder Pods.
+	// Defaults to the OPERATOR_IMAGE environment variable, or to the image of the operator Pod,
+	// when it can be retrieved from the POD_NAME and POD_NAMESPACE environment variables.
+	// +optional
+	OperatorImage string `json:"operatorImage,omitempty"`
+
+	// The initializer of the consumer workspaces, that are only released once they are provisioned.
+	// +optional
+	Initializer *Initializer `json:"initializer,omitempty"`
+
+	// The metrics exposed by the service.
+	// +optional
+	Metrics Metrics `json:"metrics,omitempty"`
+
+	// The host name the services of the consumer workspaces are exposed on,
+	// that's available to the manifest templates. The Kaoto ingress host,
+	// if set, takes precedence for the Kaoto manifest templates.
+	// +optional
+	IngressHost string `json:"ingressHost,omitempty"`
+}
+
+// Metrics configures the metrics exposed by the service.
+type Metrics struct {
+	// The maximum number of logical clusters the per-workspace metrics are labelled with.
+	// The metrics of the other logical clusters are aggregated under the `other` label value,
+	// so that the cardinality of the metrics is bounded. Zero aggregates all the logical clusters.
+	// Defaults to 100.
+	// +optional
+	MaxLogicalClusters *int32 `json:"maxLogicalClusters,omitempty"`
+}
+
+// MaxLogicalClustersOrDefault returns the maximum number of logical clusters, or the default one if not set.
+func (in *Metrics) MaxLogicalClustersOrDefault() int32 {
+	if in.MaxLogicalClusters == nil {
+		return DefaultMaxLogicalClusters
+	}
+	return *in.MaxLogicalClusters
+}
+
+// Initializer configures the initialization of the consumer workspaces.
+type Initializer struct {
+	// The WorkspaceTypes that declare camel-kcp as their initializer.
+	// The workspaces of these types are released once the resources configured on APIBinding are ready.
+	WorkspaceTypes []WorkspaceTypeReference `json:"workspaceTypes,omitempty"`
+}
+
+// WorkspaceTypeReference references a WorkspaceType by its workspace path and name.
+type WorkspaceTypeReference struct {
+	// The logical cluster path of the workspace of the WorkspaceType.
+	// Defaults to root.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// The name of the WorkspaceType.
+	Name string `json:"name"`
 }
 
 type APIExports struct {
@@ -53,6 +116,20 @@ type APIExports struct {
 	CamelK CamelKAPIExport `json:"camel-k,omitempty"`
 	// The Kaoto APIExport used to configure the Kaoto manager.
 	Kaoto KaotoAPIExport `json:"kaoto,omitempty"`
+	// The add-on APIExports, e.g., providing additional tooling, each used to configure
+	// a manager that applies the add-on manifests into the consumer workspaces.
+	// +optional
+	AddOns []AddOnAPIExport `json:"addOns,omitempty"`
+}
+
+// AddOn returns the add-on APIExport with the given name, or nil if not found.
+func (in *APIExports) AddOn(name string) *AddOnAPIExport {
+	for i := range in.AddOns {
+		if in.AddOns[i].APIExportName == name {
+			return &in.AddOns[i]
+		}
+	}
+	return nil
 }
 
 type CamelKAPIExport struct {
@@ -62,6 +139,10 @@ type CamelKAPIExport struct {
 	// The desired state of the consumer workspace when the
 	// Camel K APIExport is bound into it.
 	OnAPIBinding OnCamelKAPIBinding `json:"onApiBinding,omitempty"`
+
+	// What happens to the resources created in the consumer workspace
+	// when the Camel K APIExport is unbound from it.
+	OnAPIUnbinding OnAPIUnbinding `json:"onApiUnbinding,omitempty"`
 }
 
 type KaotoAPIExport struct {
@@ -71,6 +152,43 @@ type KaotoAPIExport struct {
 	// The desired state of the consumer workspace when the
 	// Kaoto APIExport is bound into it.
 	OnAPIBinding OnKaotoAPIBinding `json:"onApiBinding,omitempty"`
+
+	// What happens to the resources created in the consumer workspace
+	// when the Kaoto APIExport is unbound from it.
+	OnAPIUnbinding OnAPIUnbinding `json:"onApiUnbinding,omitempty"`
+}
+
+// AddOnAPIExport configures an add-on APIExport, whose resources are declared as manifests,
+// rather than provisioned by a dedicated controller.
+type AddOnAPIExport struct {
+	// The reference to the add-on APIExport.
+	LocalAPIExportReference `json:",inline,omitempty"`
+
+	// The desired state of the consumer workspace when the
+	// add-on APIExport is bound into it.
+	OnAPIBinding OnAddOnAPIBinding `json:"onApiBinding,omitempty"`
+
+	// What happens to the resources created in the consumer workspace
+	// when the add-on APIExport is unbound from it.
+	OnAPIUnbinding OnAPIUnbinding `json:"onApiUnbinding,omitempty"`
+}
+
+type OnAddOnAPIBinding struct {
+	// The specification of the default placement, that's created
+	// when the add-on APIExport is bound, in the consumer workspace.
+	// Its name must differ from those of the placements of the other
+	// APIExports, as it's deleted when the add-on APIExport is unbound.
+	// +optional
+	DefaultPlacement *Placement `json:"createDefaultPlacement,omitempty"`
+
+	// The manifest templates of the resources, that are server-side applied, in order,
+	// in the consumer workspace, when the add-on APIExport is bound into it, and when the
+	// configuration is reloaded. The resources whose manifests are removed are deleted,
+	// according to the cleanup policy. Their APIs must be served by the add-on APIExport
+	// virtual workspace, i.e., exported or claimed by the add-on APIExport.
+	// See ManifestValues for the template values.
+	// +optional
+	Manifests []runtime.RawExtension `json:"manifests,omitempty"`
 }
 
 type OnCamelKAPIBinding struct {
@@ -84,6 +202,98 @@ type OnCamelKAPIBinding struct {
 	// when the service APIExport is bound, in the consumer workspace.
 	// +optional
 	DefaultPlacement *Placement `json:"createDefaultPlacement,omitempty"`
+
+	// The overrides of the default integration platform and placement,
+	// for the consumer workspaces they select. The overrides that select
+	// a workspace are merged over the defaults, in order.
+	// +optional
+	Overrides []CamelKOverride `json:"overrides,omitempty"`
+
+	// Provisions a container registry organization, and the registry credentials,
+	// for each consumer workspace, and configures them into the default integration
+	// platform build registry.
+	// +optional
+	ProvisionRegistry *RegistryProvisioning `json:"provisionRegistry,omitempty"`
+
+	// The manifest templates of the resources, that are applied as those of the add-on APIExports,
+	// once the other resources are provisioned. Their APIs must be exported or claimed by the
+	// Camel K APIExport. See OnAddOnAPIBinding.
+	// +optional
+	Manifests []runtime.RawExtension `json:"manifests,omitempty"`
+}
+
+// RegistryProvisioning configures the provisioning of a container registry organization,
+// i.e., a repository path, and of the registry credentials, for each consumer workspace.
+type RegistryProvisioning struct {
+	// The prefix of the registry organization of each consumer workspace,
+	// that's suffixed with the workspace logical cluster name.
+	// +optional
+	OrganizationPrefix string `json:"organizationPrefix,omitempty"`
+
+	// The Secret, in the service workspace, holding the registry credentials,
+	// of type kubernetes.io/dockerconfigjson. The Secret with the same name,
+	// suffixed with -<logical cluster name>, is used for each consumer workspace,
+	// so that each workspace is given its own credentials. The workspace is not
+	// given credentials when it does not exist, unless the shared credentials are
+	// allowed, and the default integration platform registry secret, if any, is used.
+	// The namespace defaults to the status namespace.
+	CredentialsSecret SecretReference `json:"credentialsSecret"`
+
+	// Allows the Secret, with the name of the credentials Secret, to be shared by
+	// the consumer workspaces that are not given their own credentials. Note the
+	// registry organizations are then only isolated by convention, as the shared
+	// credentials can push to the whole registry.
+	// +optional
+	AllowSharedCredentials bool `json:"allowSharedCredentials,omitempty"`
+
+	// The name of the Secret holding the registry credentials, that's created
+	// in the integration platform namespace, in the consumer workspace.
+	// Defaults to camel-k-registry.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+type SecretReference struct {
+	// The namespace of the Secret.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// The name of the Secret.
+	Name string `json:"name"`
+}
+
+// CamelKOverride overrides the default integration platform and placement,
+// for the consumer workspaces it selects. The consumer workspaces are selected
+// by their logical cluster names, or by their workspace paths, rather than by
+// the labels of their APIBinding, that the tenants can change. At least one of
+// the selectors must be set, and a workspace is selected if it matches any of them.
+type CamelKOverride struct {
+	// The name of the override.
+	Name string `json:"name"`
+
+	// Selects the consumer workspaces by their logical cluster names.
+	// +optional
+	LogicalClusters []string `json:"logicalClusters,omitempty"`
+
+	// Selects the consumer workspaces by their workspace paths, or by the path
+	// of one of their parent workspaces, e.g., root:org-a selects root:org-a:team-a.
+	// The workspace paths are recorded by the initializer, from the LogicalCluster
+	// of the consumer workspaces, so that only the workspaces of the initialized
+	// WorkspaceTypes are selected.
+	// +optional
+	WorkspacePaths []string `json:"workspacePaths,omitempty"`
+
+	// The partial integration platform, that's merged over the default
+	// integration platform, as a JSON merge patch.
+	// The metadata name cannot be overridden.
+	// +optional
+	Platform *runtime.RawExtension `json:"platform,omitempty"`
+
+	// The partial placement, that's merged over the default placement,
+	// as a JSON merge patch.
+	// The metadata name cannot be overridden.
+	// +optional
+	Placement *runtime.RawExtension `json:"placement,omitempty"`
 }
 
 type OnKaotoAPIBinding struct {
@@ -91,6 +301,181 @@ type OnKaotoAPIBinding struct {
 	// when the service APIExport is bound, in the consumer workspace.
 	// +optional
 	DefaultPlacement *Placement `json:"createDefaultPlacement,omitempty"`
+
+	// The specification of the Kaoto deployment, that's created
+	// when the Kaoto APIExport is bound, in the consumer workspace.
+	// +optional
+	Kaoto KaotoSpec `json:"kaoto,omitempty"`
+
+	// The manifest templates of the resources, that are applied as those of the add-on APIExports,
+	// once the other resources are provisioned. Their APIs must be exported or claimed by the
+	// Kaoto APIExport. See OnAddOnAPIBinding.
+	// +optional
+	Manifests []runtime.RawExtension `json:"manifests,omitempty"`
+}
+
+// KaotoNamespace is the namespace where Kaoto is deployed, in the consumer workspaces.
+// The permission claims of the Kaoto APIExport are scoped to it.
+const KaotoNamespace = "kaoto"
+
+// Note the default placement of the Kaoto APIExport, if any, must select it.
+type KaotoSpec struct {
+	// The Kaoto UI component.
+	// +optional
+	UI KaotoComponent `json:"ui,omitempty"`
+
+	// The Kaoto backend component.
+	// +optional
+	Backend KaotoComponent `json:"backend,omitempty"`
+
+	// The node selector of the Kaoto pods.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// The secrets used to pull the Kaoto images, in the Kaoto namespace.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// The Ingress exposing the Kaoto UI.
+	// +optional
+	Ingress KaotoIngress `json:"ingress,omitempty"`
+}
+
+type KaotoComponent struct {
+	// The container image. Defaults to the latest image of the component.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The container image pull policy.
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// The number of replicas. Defaults to 1.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// The container compute resources.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// The container environment variables.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// IngressProfile describes how the Kaoto UI is exposed.
+// +kubebuilder:validation:Enum=Nginx;OpenShiftRoute;GatewayHTTPRoute
+type IngressProfile string
+
+const (
+	// IngressProfileNginx exposes the Kaoto UI with an Ingress, served by the NGINX Ingress controller.
+	IngressProfileNginx IngressProfile = "Nginx"
+	// IngressProfileOpenShiftRoute exposes the Kaoto UI with an OpenShift Route.
+	IngressProfileOpenShiftRoute IngressProfile = "OpenShiftRoute"
+	// IngressProfileGatewayHTTPRoute exposes the Kaoto UI with a Gateway API HTTPRoute.
+	IngressProfileGatewayHTTPRoute IngressProfile = "GatewayHTTPRoute"
+)
+
+type KaotoIngress struct {
+	// The ingress profile. Defaults to Nginx.
+	// Note the OpenShiftRoute and GatewayHTTPRoute profiles require the Kaoto
+	// APIExport to claim the routes, respectively the httproutes, resources.
+	// +optional
+	Profile IngressProfile `json:"profile,omitempty"`
+
+	// The name of the IngressClass. Only applies to the Nginx profile.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// The annotations of the resource exposing the Kaoto UI. Defaults to the
+	// annotations required by the profile to rewrite the Kaoto UI path. For the
+	// Nginx profile, the path is only a regular expression, rewritten by the default
+	// annotations, when they are not configured, and the ingress class is nginx.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// The host name the Kaoto UI is served from, under the /<logical-cluster>/kaoto path.
+	// It defaults to the load balancer host name or IP for the Nginx profile, and to the
+	// generated host name for the OpenShiftRoute profile. It's required by the
+	// GatewayHTTPRoute profile.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// The TLS configuration. The Kaoto UI is served over HTTPS when it's set.
+	// +optional
+	TLS *KaotoIngressTLS `json:"tls,omitempty"`
+
+	// The Gateway the HTTPRoute is attached to. Only applies to,
+	// and is required by, the GatewayHTTPRoute profile.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// ProfileOrDefault returns the ingress profile, defaulting to Nginx.
+func (in *KaotoIngress) ProfileOrDefault() IngressProfile {
+	if in.Profile != "" {
+		return in.Profile
+	}
+	return IngressProfileNginx
+}
+
+type KaotoIngressTLS struct {
+	// The name of the Secret, in the Kaoto namespace, holding the TLS certificate.
+	// Only applies to the Nginx profile. Routes are terminated at the edge with the
+	// router default certificate, and the HTTPRoute TLS configuration is owned
+	// by the Gateway listener.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+type GatewayReference struct {
+	// The name of the Gateway.
+	Name string `json:"name"`
+
+	// The namespace of the Gateway. Defaults to the Kaoto namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// The name of the Gateway listener.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// CleanupPolicy describes what happens to a resource created in a consumer workspace,
+// when the APIExport is unbound from it.
+// +kubebuilder:validation:Enum=Delete;Retain
+type CleanupPolicy string
+
+const (
+	// CleanupPolicyDelete deletes the resource.
+	CleanupPolicyDelete CleanupPolicy = "Delete"
+	// CleanupPolicyRetain leaves the resource in the consumer workspace.
+	CleanupPolicyRetain CleanupPolicy = "Retain"
+)
+
+type OnAPIUnbinding struct {
+	// The cleanup policy of the resources created in the consumer workspace.
+	// Defaults to Delete.
+	// Note the namespaced resources are deleted along with their namespace,
+	// unless the Namespace kind is retained.
+	// +optional
+	CleanupPolicy CleanupPolicy `json:"cleanupPolicy,omitempty"`
+
+	// The cleanup policies per resource kind, e.g., Namespace or Placement,
+	// that override the default cleanup policy.
+	// +optional
+	CleanupPolicies map[string]CleanupPolicy `json:"cleanupPolicies,omitempty"`
+}
+
+// CleanupPolicyFor returns the cleanup policy of the resources of the given kind.
+func (in *OnAPIUnbinding) CleanupPolicyFor(kind string) CleanupPolicy {
+	if policy, ok := in.CleanupPolicies[kind]; ok {
+		return policy
+	}
+	if in.CleanupPolicy != "" {
+		return in.CleanupPolicy
+	}
+	return CleanupPolicyDelete
 }
 
 type IntegrationPlatform struct {
@@ -112,4 +497,19 @@ type LocalAPIExportReference struct {
 	// +kubebuilder:validation:Required
 	// +kube:validation:MinLength=1
 	APIExportName string `json:"apiExportName"`
+
+	// APIExportEndpointSliceName is the name of the APIExportEndpointSlice
+	// that lists the APIExport virtual workspace URLs, one per shard.
+	// It defaults to the APIExport name.
+	//
+	// +optional
+	APIExportEndpointSliceName string `json:"apiExportEndpointSliceName,omitempty"`
+}
+
+// EndpointSliceName returns the name of the APIExportEndpointSlice for the referenced APIExport.
+func (in LocalAPIExportReference) EndpointSliceName() string {
+	if in.APIExportEndpointSliceName != "" {
+		return in.APIExportEndpointSliceName
+	}
+	return in.APIExportName
 }
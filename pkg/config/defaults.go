@@ -0,0 +1,109 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+const (
+	// DefaultStatusNamespace is the default namespace where the provisioning status of the consumer workspaces is aggregated.
+	DefaultStatusNamespace = "camel-kcp"
+	// DefaultRegistrySecretName is the default name of the registry credentials Secret created in the consumer workspaces.
+	DefaultRegistrySecretName = "camel-k-registry"
+	// DefaultWorkspaceTypePath is the default logical cluster path of the WorkspaceTypes declaring camel-kcp as their initializer.
+	DefaultWorkspaceTypePath = "root"
+	// DefaultMaxLogicalClusters is the default maximum number of logical clusters the per-workspace metrics are labelled with.
+	DefaultMaxLogicalClusters = 100
+)
+
+// Default sets the default values of the service configuration fields that are not set.
+func (in *ServiceConfiguration) Default() {
+	if in.Service.StatusNamespace == "" {
+		in.Service.StatusNamespace = DefaultStatusNamespace
+	}
+
+	if initializer := in.Service.Initializer; initializer != nil {
+		for i := range initializer.WorkspaceTypes {
+			initializer.WorkspaceTypes[i].setDefaults()
+		}
+	}
+
+	in.Service.Metrics.setDefaults()
+
+	camelK := &in.Service.APIExports.CamelK
+	camelK.LocalAPIExportReference.setDefaults()
+	camelK.OnAPIUnbinding.setDefaults()
+	if registry := camelK.OnAPIBinding.ProvisionRegistry; registry != nil {
+		registry.setDefaults(in.Service.StatusNamespace)
+	}
+
+	kaoto := &in.Service.APIExports.Kaoto
+	kaoto.LocalAPIExportReference.setDefaults()
+	kaoto.OnAPIUnbinding.setDefaults()
+	kaoto.OnAPIBinding.Kaoto.setDefaults()
+
+	for i := range in.Service.APIExports.AddOns {
+		addOn := &in.Service.APIExports.AddOns[i]
+		addOn.LocalAPIExportReference.setDefaults()
+		addOn.OnAPIUnbinding.setDefaults()
+	}
+}
+
+func (in *LocalAPIExportReference) setDefaults() {
+	if in.APIExportEndpointSliceName == "" {
+		in.APIExportEndpointSliceName = in.APIExportName
+	}
+}
+
+func (in *WorkspaceTypeReference) setDefaults() {
+	if in.Path == "" {
+		in.Path = DefaultWorkspaceTypePath
+	}
+}
+
+func (in *Metrics) setDefaults() {
+	if in.MaxLogicalClusters == nil {
+		maxLogicalClusters := in.MaxLogicalClustersOrDefault()
+		in.MaxLogicalClusters = &maxLogicalClusters
+	}
+}
+
+func (in *OnAPIUnbinding) setDefaults() {
+	if in.CleanupPolicy == "" {
+		in.CleanupPolicy = CleanupPolicyDelete
+	}
+}
+
+func (in *RegistryProvisioning) setDefaults(namespace string) {
+	if in.CredentialsSecret.Namespace == "" {
+		in.CredentialsSecret.Namespace = namespace
+	}
+	if in.SecretName == "" {
+		in.SecretName = DefaultRegistrySecretName
+	}
+}
+
+func (in *KaotoSpec) setDefaults() {
+	in.UI.setDefaults()
+	in.Backend.setDefaults()
+	in.Ingress.Profile = in.Ingress.ProfileOrDefault()
+}
+
+func (in *KaotoComponent) setDefaults() {
+	if in.Replicas == nil {
+		replicas := int32(1)
+		in.Replicas = &replicas
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestDefault(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := &ServiceConfiguration{}
+	cfg.Service.APIExports.CamelK.APIExportName = "camel-k"
+	cfg.Service.APIExports.Kaoto.APIExportName = "kaoto"
+	cfg.Default()
+
+	g.Expect(cfg.Service.StatusNamespace).To(Equal(DefaultStatusNamespace))
+	g.Expect(cfg.Service.Metrics.MaxLogicalClusters).To(Equal(pointer.Int32(DefaultMaxLogicalClusters)))
+
+	camelK := cfg.Service.APIExports.CamelK
+	g.Expect(camelK.APIExportEndpointSliceName).To(Equal("camel-k"))
+	g.Expect(camelK.OnAPIUnbinding.CleanupPolicy).To(Equal(CleanupPolicyDelete))
+	g.Expect(camelK.OnAPIBinding.DefaultPlatform).To(BeNil())
+	g.Expect(camelK.OnAPIBinding.DefaultPlacement).To(BeNil())
+
+	kaoto := cfg.Service.APIExports.Kaoto
+	g.Expect(kaoto.APIExportEndpointSliceName).To(Equal("kaoto"))
+	g.Expect(kaoto.OnAPIUnbinding.CleanupPolicy).To(Equal(CleanupPolicyDelete))
+	g.Expect(kaoto.OnAPIBinding.Kaoto.UI.Replicas).To(Equal(pointer.Int32(1)))
+	g.Expect(kaoto.OnAPIBinding.Kaoto.Backend.Replicas).To(Equal(pointer.Int32(1)))
+	g.Expect(kaoto.OnAPIBinding.Kaoto.Ingress.Profile).To(Equal(IngressProfileNginx))
+
+	g.Expect(cfg.Validate()).To(BeEmpty())
+}
+
+func TestDefaultRegistryProvisioning(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := validServiceConfiguration()
+	cfg.Service.StatusNamespace = "camel-kcp-system"
+	cfg.Service.APIExports.CamelK.OnAPIBinding.ProvisionRegistry = &RegistryProvisioning{
+		CredentialsSecret: SecretReference{Name: "registry"},
+	}
+	cfg.Default()
+
+	g.Expect(cfg.Service.APIExports.CamelK.OnAPIBinding.ProvisionRegistry).To(Equal(&RegistryProvisioning{
+		CredentialsSecret: SecretReference{Namespace: "camel-kcp-system", Name: "registry"},
+		SecretName:        DefaultRegistrySecretName,
+	}))
+}
+
+func TestDefaultInitializer(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := validServiceConfiguration()
+	cfg.Service.Initializer = &Initializer{WorkspaceTypes: []WorkspaceTypeReference{
+		{Name: "camel-k"},
+		{Path: "root:org", Name: "camel-k"},
+	}}
+	cfg.Default()
+
+	g.Expect(cfg.Service.Initializer.WorkspaceTypes).To(Equal([]WorkspaceTypeReference{
+		{Path: DefaultWorkspaceTypePath, Name: "camel-k"},
+		{Path: "root:org", Name: "camel-k"},
+	}))
+}
+
+func TestDefaultAddOns(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := validServiceConfiguration()
+	cfg.Service.APIExports.AddOns = append(cfg.Service.APIExports.AddOns, AddOnAPIExport{
+		LocalAPIExportReference: LocalAPIExportReference{
+			APIExportName:              "strimzi",
+			APIExportEndpointSliceName: "strimzi-endpoints",
+		},
+		OnAPIUnbinding: OnAPIUnbinding{CleanupPolicy: CleanupPolicyRetain},
+	})
+	cfg.Default()
+
+	hawtio := cfg.Service.APIExports.AddOn("hawtio")
+	g.Expect(hawtio).NotTo(BeNil())
+	g.Expect(hawtio.APIExportEndpointSliceName).To(Equal("hawtio"))
+	g.Expect(hawtio.OnAPIUnbinding.CleanupPolicy).To(Equal(CleanupPolicyDelete))
+
+	strimzi := cfg.Service.APIExports.AddOn("strimzi")
+	g.Expect(strimzi).NotTo(BeNil())
+	g.Expect(strimzi.APIExportEndpointSliceName).To(Equal("strimzi-endpoints"))
+	g.Expect(strimzi.OnAPIUnbinding.CleanupPolicy).To(Equal(CleanupPolicyRetain))
+
+	g.Expect(cfg.Service.APIExports.AddOn("other")).To(BeNil())
+}
+
+func TestDefaultPreservesValues(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := validServiceConfiguration()
+	cfg.Service.Metrics.MaxLogicalClusters = pointer.Int32(0)
+	cfg.Service.APIExports.CamelK.APIExportEndpointSliceName = "camel-k-endpoints"
+	cfg.Service.APIExports.CamelK.OnAPIUnbinding.CleanupPolicy = CleanupPolicyRetain
+	cfg.Service.APIExports.Kaoto.OnAPIBinding.Kaoto.UI.Replicas = pointer.Int32(0)
+	cfg.Service.APIExports.Kaoto.OnAPIBinding.Kaoto.Ingress.Profile = IngressProfileOpenShiftRoute
+	expected := cfg.DeepCopy()
+	expected.Service.APIExports.Kaoto.APIExportEndpointSliceName = "kaoto"
+	expected.Service.APIExports.Kaoto.OnAPIBinding.Kaoto.Backend.Replicas = pointer.Int32(1)
+	expected.Service.APIExports.AddOns[0].APIExportEndpointSliceName = "hawtio"
+	expected.Service.APIExports.AddOns[0].OnAPIUnbinding.CleanupPolicy = CleanupPolicyDelete
+
+	cfg.Default()
+
+	g.Expect(cfg).To(Equal(expected))
+}
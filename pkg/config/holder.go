@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Holder holds the current service configuration, that can be swapped atomically when it's reloaded.
+// The configuration returned by Get must be treated as read-only.
+// +kubebuilder:object:generate=false
+type Holder struct {
+	current     atomic.Pointer[ServiceConfiguration]
+	lock        sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+// NewHolder returns a Holder for the given initial configuration.
+func NewHolder(cfg *ServiceConfiguration) *Holder {
+	h := &Holder{
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+	h.current.Store(cfg)
+	return h
+}
+
+// Get returns the current configuration.
+func (h *Holder) Get() *ServiceConfiguration {
+	return h.current.Load()
+}
+
+// Set swaps the current configuration, and notifies the subscribers.
+func (h *Holder) Set(cfg *ServiceConfiguration) {
+	h.current.Store(cfg)
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for subscriber := range h.subscribers {
+		// Notifications are coalesced for the subscribers that have not yet received the previous one
+		select {
+		case subscriber <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel that receives a notification every time the configuration is swapped.
+// The channel is closed when the context is cancelled.
+func (h *Holder) Subscribe(ctx context.Context) <-chan struct{} {
+	subscriber := make(chan struct{}, 1)
+
+	h.lock.Lock()
+	h.subscribers[subscriber] = struct{}{}
+	h.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		delete(h.subscribers, subscriber)
+		close(subscriber)
+	}()
+
+	return subscriber
+}
@@ -0,0 +1,135 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// ManifestValues are the values the manifest templates are executed with, for a consumer workspace.
+type ManifestValues struct {
+	// The logical cluster name of the consumer workspace.
+	LogicalCluster string
+	// The logical cluster path of the consumer workspace.
+	WorkspacePath string
+	// The host name the services of the consumer workspace are exposed on.
+	IngressHost string
+}
+
+// validationValues are the values the manifest templates are executed with, when the configuration is validated.
+var validationValues = &ManifestValues{
+	LogicalCluster: "cluster",
+	WorkspacePath:  "root:workspace",
+	IngressHost:    "example.com",
+}
+
+// RenderManifests executes the manifest templates with the given values, and decodes them into objects, in order.
+// The templates are the string values, and the map keys, of the manifests, e.g.:
+//
+//	metadata:
+//	  name: {{ .LogicalCluster }}-config
+func RenderManifests(manifests []runtime.RawExtension, values *ManifestValues) ([]*unstructured.Unstructured, error) {
+	objects := make([]*unstructured.Unstructured, 0, len(manifests))
+	for i := range manifests {
+		obj, err := renderManifest(&manifests[i], values)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering manifest %d: %w", i, err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// renderManifest renders the manifest of a single object, that must declare its apiVersion, kind and name.
+func renderManifest(manifest *runtime.RawExtension, values *ManifestValues) (*unstructured.Unstructured, error) {
+	var object map[string]interface{}
+	if err := json.Unmarshal(manifest.Raw, &object); err != nil {
+		return nil, err
+	}
+	rendered, err := render(object, values)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{Object: rendered.(map[string]interface{})}
+	if obj.IsList() {
+		return nil, errors.New("lists are not supported")
+	}
+	if obj.GetAPIVersion() == "" {
+		return nil, errors.New("apiVersion must be set")
+	}
+	if obj.GetKind() == "" {
+		return nil, errors.New("kind must be set")
+	}
+	if obj.GetName() == "" {
+		return nil, errors.New("metadata.name must be set")
+	}
+	return obj, nil
+}
+
+func render(value interface{}, values *ManifestValues) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderString(v, values)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			renderedKey, err := renderString(key, values)
+			if err != nil {
+				return nil, err
+			}
+			if out[renderedKey], err = render(element, values); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, element := range v {
+			var err error
+			if out[i], err = render(element, values); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+func renderString(s string, values *ManifestValues) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	t, err := template.New("manifest").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, values); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRenderManifests(t *testing.T) {
+	values := &ManifestValues{
+		LogicalCluster: "2a3b4c",
+		WorkspacePath:  "root:org:team",
+		IngressHost:    "apps.example.com",
+	}
+
+	t.Run("templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := RenderManifests([]runtime.RawExtension{
+			{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"tenant-{{ .LogicalCluster }}"}}`)},
+			{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"endpoints","namespace":"tenant-{{ .LogicalCluster }}","labels":{"{{ .LogicalCluster }}":"true"}},"data":{"path":"{{ .WorkspacePath }}","url":"https://{{ .IngressHost }}/hawtio"}}`)},
+		}, values)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(objects).To(HaveLen(2))
+
+		g.Expect(objects[0].GetName()).To(Equal("tenant-2a3b4c"))
+		g.Expect(objects[1].GetNamespace()).To(Equal("tenant-2a3b4c"))
+		g.Expect(objects[1].GetLabels()).To(Equal(map[string]string{"2a3b4c": "true"}))
+		g.Expect(objects[1].Object["data"]).To(Equal(map[string]interface{}{
+			"path": "root:org:team",
+			"url":  "https://apps.example.com/hawtio",
+		}))
+	})
+
+	t.Run("non string values", func(t *testing.T) {
+		g := NewWithT(t)
+
+		objects, err := RenderManifests([]runtime.RawExtension{
+			{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"hawtio"},"spec":{"replicas":2,"paused":false,"template":{"spec":{"containers":[{"name":"hawtio","args":["{{ .WorkspacePath }}"]}]}}}}`)},
+		}, values)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		// Integers are decoded as int64, so that the objects can be converted to typed objects
+		g.Expect(objects[0].Object["spec"]).To(HaveKeyWithValue("replicas", int64(2)))
+		g.Expect(objects[0].Object["spec"]).To(HaveKeyWithValue("paused", false))
+		g.Expect(objects[0].Object["spec"]).To(HaveKeyWithValue("template", map[string]interface{}{
+			"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "hawtio", "args": []interface{}{"root:org:team"}},
+				},
+			},
+		}))
+	})
+
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{name: "missing value", manifest: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .Unknown }}"}}`},
+		{name: "invalid template", manifest: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .LogicalCluster"}}`},
+		{name: "invalid key template", manifest: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config"},"data":{"{{ end }}":""}}`},
+		{name: "empty name", manifest: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ if false }}config{{ end }}"}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := RenderManifests([]runtime.RawExtension{
+				{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"hawtio"}}`)},
+				{Raw: []byte(test.manifest)},
+			}, values)
+			g.Expect(err).To(MatchError(ContainSubstring("error rendering manifest 1")))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ForWorkspace returns the configuration for the consumer workspace, identified by its logical cluster name,
+// and its workspace path, that's empty if unknown, where the overrides that select the workspace are merged
+// over the defaults.
+func (in *OnCamelKAPIBinding) ForWorkspace(cluster, path string) (*OnCamelKAPIBinding, error) {
+	out := in.DeepCopy()
+	out.Overrides = nil
+
+	var err error
+	for i := range in.Overrides {
+		override := &in.Overrides[i]
+		if !override.Matches(cluster, path) {
+			continue
+		}
+		if override.Platform != nil {
+			if out.DefaultPlatform, err = merge(out.DefaultPlatform, override.Platform); err != nil {
+				return nil, fmt.Errorf("error merging override %s platform: %w", override.Name, err)
+			}
+		}
+		if override.Placement != nil {
+			if out.DefaultPlacement, err = merge(out.DefaultPlacement, override.Placement); err != nil {
+				return nil, fmt.Errorf("error merging override %s placement: %w", override.Name, err)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// Matches returns whether the override selects the consumer workspace, identified by its logical cluster name,
+// and its workspace path, that's empty if unknown.
+func (in *CamelKOverride) Matches(cluster, path string) bool {
+	for _, c := range in.LogicalClusters {
+		if c == cluster {
+			return true
+		}
+	}
+	if path == "" {
+		return false
+	}
+	for _, p := range in.WorkspacePaths {
+		if path == p || strings.HasPrefix(path, p+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// merge applies the patch over the base object, using JSON merge patch semantics.
+// The base object is left untouched, and may be nil.
+func merge[T any](base *T, patch *runtime.RawExtension) (*T, error) {
+	original := []byte("{}")
+	if base != nil {
+		data, err := json.Marshal(base)
+		if err != nil {
+			return nil, err
+		}
+		original = data
+	}
+	merged, err := jsonpatch.MergePatch(original, patch.Raw)
+	if err != nil {
+		return nil, err
+	}
+	out := new(T)
+	if err := json.Unmarshal(merged, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
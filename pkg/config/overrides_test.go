@@ -0,0 +1,132 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestForWorkspace(t *testing.T) {
+	onBinding := validServiceConfiguration().Service.APIExports.CamelK.OnAPIBinding
+	onBinding.Overrides = []CamelKOverride{
+		{
+			Name:            "team-a",
+			LogicalClusters: []string{"team-a", "secure"},
+			Platform:        &runtime.RawExtension{Raw: []byte(`{"spec":{"build":{"registry":{"address":"registry.team-a.example.com"}}}}`)},
+			Placement:       &runtime.RawExtension{Raw: []byte(`{"spec":{"locationSelectors":[{"matchLabels":{"team":"a"}}]}}`)},
+		},
+		{
+			Name:            "secure",
+			LogicalClusters: []string{"secure"},
+			Platform:        &runtime.RawExtension{Raw: []byte(`{"spec":{"build":{"registry":{"secret":"secure-registry"}}}}`)},
+		},
+		{
+			Name:            "secure-timeout",
+			LogicalClusters: []string{"secure"},
+			Platform:        &runtime.RawExtension{Raw: []byte(`{"spec":{"build":{"timeout":null}}}`)},
+		},
+		{
+			Name:           "org-a",
+			WorkspacePaths: []string{"root:org-a"},
+			Platform:       &runtime.RawExtension{Raw: []byte(`{"spec":{"build":{"registry":{"organization":"org-a"}}}}`)},
+		},
+	}
+
+	t.Run("no override", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cfg, err := onBinding.ForWorkspace("other", "")
+		g.Expect(err).NotTo(HaveOccurred())
+
+		expected := onBinding.DeepCopy()
+		expected.Overrides = nil
+		g.Expect(cfg).To(Equal(expected))
+	})
+
+	t.Run("logical cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cfg, err := onBinding.ForWorkspace("team-a", "root:team-a")
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(cfg.DefaultPlatform.Name).To(Equal("camel-k"))
+		g.Expect(cfg.DefaultPlatform.Spec.Build.Registry.Address).To(Equal("registry.team-a.example.com"))
+		g.Expect(cfg.DefaultPlatform.Spec.Build.Registry.Secret).To(Equal("registry-credentials"))
+		g.Expect(cfg.DefaultPlatform.Spec.Build.Timeout).NotTo(BeNil())
+		g.Expect(cfg.DefaultPlacement.Spec.LocationSelectors).To(ConsistOf(metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}))
+		g.Expect(cfg.DefaultPlacement.Spec.LocationWorkspace).To(Equal("root:camel-kcp"))
+		// The defaults are left untouched
+		g.Expect(onBinding.DefaultPlatform.Spec.Build.Registry.Address).To(Equal("registry.example.com:5000"))
+	})
+
+	t.Run("workspace path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		for _, path := range []string{"root:org-a", "root:org-a:team-a"} {
+			cfg, err := onBinding.ForWorkspace("other", path)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cfg.DefaultPlatform.Spec.Build.Registry.Organization).To(Equal("org-a"))
+		}
+
+		for _, path := range []string{"", "root", "root:org-ab", "root:other:org-a"} {
+			cfg, err := onBinding.ForWorkspace("other", path)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cfg.DefaultPlatform.Spec.Build.Registry.Organization).To(BeEmpty())
+		}
+	})
+
+	t.Run("merged in order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cfg, err := onBinding.ForWorkspace("secure", "")
+		g.Expect(err).NotTo(HaveOccurred())
+
+		g.Expect(cfg.DefaultPlatform.Spec.Build.Registry.Address).To(Equal("registry.team-a.example.com"))
+		g.Expect(cfg.DefaultPlatform.Spec.Build.Registry.Secret).To(Equal("secure-registry"))
+		g.Expect(cfg.DefaultPlatform.Spec.Build.Timeout).To(BeNil())
+	})
+
+	t.Run("without defaults", func(t *testing.T) {
+		g := NewWithT(t)
+
+		onBinding := &OnCamelKAPIBinding{
+			Overrides: []CamelKOverride{
+				{
+					Name:            "secure",
+					LogicalClusters: []string{"secure"},
+					Platform:        &runtime.RawExtension{Raw: []byte(`{"spec":{"build":{"registry":{"address":"registry.example.com"}}}}`)},
+				},
+			},
+		}
+
+		cfg, err := onBinding.ForWorkspace("other", "")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cfg.DefaultPlatform).To(BeNil())
+
+		cfg, err = onBinding.ForWorkspace("secure", "")
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(cfg.DefaultPlatform).NotTo(BeNil())
+		g.Expect(cfg.DefaultPlatform.Spec.Build.Registry.Address).To(Equal("registry.example.com"))
+		g.Expect(cfg.DefaultPlacement).To(BeNil())
+	})
+}
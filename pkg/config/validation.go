@@ -0,0 +1,616 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+const restartRequired = "cannot be changed without restarting the service"
+
+var (
+	cleanupPolicies   = []string{string(CleanupPolicyDelete), string(CleanupPolicyRetain)}
+	ingressProfiles   = []string{string(IngressProfileNginx), string(IngressProfileOpenShiftRoute), string(IngressProfileGatewayHTTPRoute)}
+	imagePullPolicies = []string{string(corev1.PullAlways), string(corev1.PullIfNotPresent), string(corev1.PullNever)}
+
+	// The organization prefix is either a sequence of repository path components, or the beginning of one
+	registryOrganizationPrefixRegexp = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+|/)[a-z0-9]+)*(\.|_|__|-+|/)?$`)
+)
+
+// Validate validates the service configuration, and returns the errors with the paths of the invalid fields.
+func (in *ServiceConfiguration) Validate() field.ErrorList {
+	var errs field.ErrorList
+
+	servicePath := field.NewPath("service")
+	if in.Service.StatusNamespace != "" {
+		errs = append(errs, validateDNS1123Label(in.Service.StatusNamespace, servicePath.Child("statusNamespace"))...)
+	}
+	if strings.ContainsAny(in.Service.OperatorImage, " \t\n") {
+		errs = append(errs, field.Invalid(servicePath.Child("operatorImage"), in.Service.OperatorImage, "must not contain whitespaces"))
+	}
+
+	if in.Service.Initializer != nil {
+		errs = append(errs, in.Service.Initializer.validate(servicePath.Child("initializer"))...)
+	}
+
+	if in.Service.IngressHost != "" {
+		errs = append(errs, validateDNS1123Subdomain(in.Service.IngressHost, servicePath.Child("ingressHost"))...)
+	}
+
+	if max := in.Service.Metrics.MaxLogicalClusters; max != nil && *max < 0 {
+		errs = append(errs, field.Invalid(servicePath.Child("metrics", "maxLogicalClusters"), *max, "must be greater than or equal to 0"))
+	}
+
+	camelK := &in.Service.APIExports.CamelK
+	camelKPath := servicePath.Child("apiExports", "camel-k")
+	errs = append(errs, camelK.LocalAPIExportReference.validate(camelKPath)...)
+	errs = append(errs, camelK.OnAPIBinding.validate(camelKPath.Child("onApiBinding"))...)
+	errs = append(errs, camelK.OnAPIUnbinding.validate(camelKPath.Child("onApiUnbinding"))...)
+
+	kaoto := &in.Service.APIExports.Kaoto
+	kaotoPath := servicePath.Child("apiExports", "kaoto")
+	errs = append(errs, kaoto.LocalAPIExportReference.validate(kaotoPath)...)
+	errs = append(errs, kaoto.OnAPIBinding.validate(kaotoPath.Child("onApiBinding"))...)
+	errs = append(errs, kaoto.OnAPIUnbinding.validate(kaotoPath.Child("onApiUnbinding"))...)
+
+	names := sets.NewString(camelK.APIExportName, kaoto.APIExportName)
+	// The add-on placements are deleted by name when the add-on APIExports are unbound,
+	// so that they must not be those of the other APIExports
+	placements := sets.NewString()
+	for _, placement := range []*Placement{camelK.OnAPIBinding.DefaultPlacement, kaoto.OnAPIBinding.DefaultPlacement} {
+		if placement != nil {
+			placements.Insert(placement.Name)
+		}
+	}
+	for i := range in.Service.APIExports.AddOns {
+		addOn := &in.Service.APIExports.AddOns[i]
+		addOnPath := servicePath.Child("apiExports", "addOns").Index(i)
+		errs = append(errs, addOn.LocalAPIExportReference.validate(addOnPath)...)
+		if len(addOn.APIExportName) > validation.DNS1123LabelMaxLength {
+			// The APIExport name is used as the name of the APIBinding finalizer
+			errs = append(errs, field.TooLong(addOnPath.Child("apiExportName"), addOn.APIExportName, validation.DNS1123LabelMaxLength))
+		}
+		if names.Has(addOn.APIExportName) {
+			errs = append(errs, field.Duplicate(addOnPath.Child("apiExportName"), addOn.APIExportName))
+		}
+		names.Insert(addOn.APIExportName)
+		if placement := addOn.OnAPIBinding.DefaultPlacement; placement != nil && placement.Name != "" {
+			if placements.Has(placement.Name) {
+				errs = append(errs, field.Duplicate(addOnPath.Child("onApiBinding", "createDefaultPlacement", "metadata", "name"), placement.Name))
+			}
+			placements.Insert(placement.Name)
+		}
+		errs = append(errs, addOn.OnAPIBinding.validate(addOnPath.Child("onApiBinding"))...)
+		errs = append(errs, addOn.OnAPIUnbinding.validate(addOnPath.Child("onApiUnbinding"))...)
+	}
+
+	return errs
+}
+
+func (in *Initializer) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	references := sets.NewString()
+	for i, workspaceType := range in.WorkspaceTypes {
+		workspaceTypePath := path.Child("workspaceTypes").Index(i)
+		if workspaceType.Path != "" {
+			errs = append(errs, validateLogicalClusterPath(workspaceType.Path, workspaceTypePath.Child("path"))...)
+		}
+		if workspaceType.Name == "" {
+			errs = append(errs, field.Required(workspaceTypePath.Child("name"), ""))
+			continue
+		}
+		errs = append(errs, validateDNS1123Subdomain(workspaceType.Name, workspaceTypePath.Child("name"))...)
+		reference := workspaceType.Path + ":" + workspaceType.Name
+		if references.Has(reference) {
+			errs = append(errs, field.Duplicate(workspaceTypePath, workspaceType))
+		}
+		references.Insert(reference)
+	}
+	return errs
+}
+
+func (in *LocalAPIExportReference) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if in.APIExportName == "" {
+		errs = append(errs, field.Required(path.Child("apiExportName"), ""))
+	} else {
+		errs = append(errs, validateDNS1123Subdomain(in.APIExportName, path.Child("apiExportName"))...)
+	}
+	if in.APIExportEndpointSliceName != "" {
+		errs = append(errs, validateDNS1123Subdomain(in.APIExportEndpointSliceName, path.Child("apiExportEndpointSliceName"))...)
+	}
+	return errs
+}
+
+func (in *OnCamelKAPIBinding) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if in.DefaultPlatform != nil {
+		errs = append(errs, in.DefaultPlatform.validate(path.Child("createDefaultPlatform"))...)
+	}
+	if in.DefaultPlacement != nil {
+		errs = append(errs, in.DefaultPlacement.validate(path.Child("createDefaultPlacement"))...)
+	}
+	names := sets.NewString()
+	for i := range in.Overrides {
+		override := &in.Overrides[i]
+		overridePath := path.Child("overrides").Index(i)
+		if override.Name == "" {
+			errs = append(errs, field.Required(overridePath.Child("name"), ""))
+		} else if names.Has(override.Name) {
+			errs = append(errs, field.Duplicate(overridePath.Child("name"), override.Name))
+		}
+		names.Insert(override.Name)
+		errs = append(errs, override.validate(in, overridePath)...)
+	}
+	if in.ProvisionRegistry != nil {
+		errs = append(errs, in.ProvisionRegistry.validate(path.Child("provisionRegistry"))...)
+	}
+	errs = append(errs, validateManifests(in.Manifests, path.Child("manifests"))...)
+	return errs
+}
+
+func (in *RegistryProvisioning) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if in.OrganizationPrefix != "" && !registryOrganizationPrefixRegexp.MatchString(in.OrganizationPrefix) {
+		errs = append(errs, field.Invalid(path.Child("organizationPrefix"), in.OrganizationPrefix,
+			"must consist of lower case alphanumeric characters, separated by '.', '_', '-' or '/'"))
+	}
+	secretPath := path.Child("credentialsSecret")
+	if in.CredentialsSecret.Name == "" {
+		errs = append(errs, field.Required(secretPath.Child("name"), ""))
+	} else {
+		errs = append(errs, validateDNS1123Subdomain(in.CredentialsSecret.Name, secretPath.Child("name"))...)
+	}
+	if in.CredentialsSecret.Namespace != "" {
+		errs = append(errs, validateDNS1123Label(in.CredentialsSecret.Namespace, secretPath.Child("namespace"))...)
+	}
+	if in.SecretName != "" {
+		errs = append(errs, validateDNS1123Subdomain(in.SecretName, path.Child("secretName"))...)
+	}
+
+	return errs
+}
+
+func (in *CamelKOverride) validate(defaults *OnCamelKAPIBinding, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if len(in.LogicalClusters) == 0 && len(in.WorkspacePaths) == 0 {
+		errs = append(errs, field.Required(path.Child("logicalClusters"), "either logicalClusters or workspacePaths must be set"))
+	}
+	for i, cluster := range in.LogicalClusters {
+		if cluster == "" {
+			errs = append(errs, field.Required(path.Child("logicalClusters").Index(i), ""))
+		}
+	}
+	for i, workspacePath := range in.WorkspacePaths {
+		if workspacePath == "" {
+			errs = append(errs, field.Required(path.Child("workspacePaths").Index(i), ""))
+		} else {
+			errs = append(errs, validateLogicalClusterPath(workspacePath, path.Child("workspacePaths").Index(i))...)
+		}
+	}
+
+	// The merged resources are validated, as the overrides are partial
+	if in.Platform != nil {
+		platformPath := path.Child("platform")
+		if ip, err := merge(defaults.DefaultPlatform, in.Platform); err != nil {
+			errs = append(errs, field.Invalid(platformPath, string(in.Platform.Raw), err.Error()))
+		} else {
+			var name string
+			if defaults.DefaultPlatform != nil {
+				name = defaults.DefaultPlatform.Name
+			}
+			if ip.Name != name {
+				errs = append(errs, field.Forbidden(platformPath.Child("metadata", "name"), "cannot be overridden"))
+			}
+			errs = append(errs, ip.validate(platformPath)...)
+		}
+	}
+	if in.Placement != nil {
+		placementPath := path.Child("placement")
+		if placement, err := merge(defaults.DefaultPlacement, in.Placement); err != nil {
+			errs = append(errs, field.Invalid(placementPath, string(in.Placement.Raw), err.Error()))
+		} else {
+			if defaults.DefaultPlacement != nil && placement.Name != defaults.DefaultPlacement.Name {
+				errs = append(errs, field.Forbidden(placementPath.Child("metadata", "name"), "cannot be overridden"))
+			}
+			errs = append(errs, placement.validate(placementPath)...)
+		}
+	}
+
+	return errs
+}
+
+func (in *OnKaotoAPIBinding) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if in.DefaultPlacement != nil {
+		errs = append(errs, in.DefaultPlacement.validate(path.Child("createDefaultPlacement"))...)
+	}
+	errs = append(errs, in.Kaoto.validate(path.Child("kaoto"))...)
+	errs = append(errs, validateManifests(in.Manifests, path.Child("manifests"))...)
+	return errs
+}
+
+func (in *OnAddOnAPIBinding) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if in.DefaultPlacement != nil {
+		errs = append(errs, in.DefaultPlacement.validate(path.Child("createDefaultPlacement"))...)
+	}
+	errs = append(errs, validateManifests(in.Manifests, path.Child("manifests"))...)
+	return errs
+}
+
+// validateManifests validates the manifest templates, by rendering them with the validation values.
+func validateManifests(manifests []runtime.RawExtension, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	objects := sets.NewString()
+	for i := range manifests {
+		manifest := &manifests[i]
+		manifestPath := path.Index(i)
+		obj, err := renderManifest(manifest, validationValues)
+		if err != nil {
+			errs = append(errs, field.Invalid(manifestPath, string(manifest.Raw), err.Error()))
+			continue
+		}
+		key := obj.GroupVersionKind().GroupKind().String() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+		if objects.Has(key) {
+			errs = append(errs, field.Duplicate(manifestPath, key))
+		}
+		objects.Insert(key)
+	}
+	return errs
+}
+
+func (in *OnAPIUnbinding) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if in.CleanupPolicy != "" {
+		errs = append(errs, validateEnum(string(in.CleanupPolicy), cleanupPolicies, path.Child("cleanupPolicy"))...)
+	}
+	for kind, policy := range in.CleanupPolicies {
+		if kind == "" {
+			errs = append(errs, field.Invalid(path.Child("cleanupPolicies"), kind, "kind must not be empty"))
+		}
+		errs = append(errs, validateEnum(string(policy), cleanupPolicies, path.Child("cleanupPolicies").Key(kind))...)
+	}
+	return errs
+}
+
+func (in *IntegrationPlatform) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	metadataPath := path.Child("metadata")
+	if in.Name != "" {
+		errs = append(errs, validateDNS1123Subdomain(in.Name, metadataPath.Child("name"))...)
+	}
+	if in.Namespace != "" {
+		errs = append(errs, validateDNS1123Label(in.Namespace, metadataPath.Child("namespace"))...)
+	}
+
+	specPath := path.Child("spec")
+	if in.Spec.Cluster != "" {
+		errs = append(errs, validateEnum(string(in.Spec.Cluster), enumValues(v1.AllIntegrationPlatformClusters), specPath.Child("cluster"))...)
+	}
+	if in.Spec.Profile != "" {
+		errs = append(errs, validateEnum(string(in.Spec.Profile), enumValues(v1.AllTraitProfiles), specPath.Child("profile"))...)
+	}
+
+	build := &in.Spec.Build
+	buildPath := specPath.Child("build")
+	if build.BuildStrategy != "" {
+		errs = append(errs, validateEnum(string(build.BuildStrategy), enumValues(v1.BuildStrategies), buildPath.Child("buildStrategy"))...)
+	}
+	if build.PublishStrategy != "" {
+		errs = append(errs, validateEnum(string(build.PublishStrategy), enumValues(v1.IntegrationPlatformBuildPublishStrategies), buildPath.Child("publishStrategy"))...)
+	}
+	if build.RuntimeProvider != "" {
+		errs = append(errs, validateEnum(string(build.RuntimeProvider), []string{string(v1.RuntimeProviderQuarkus)}, buildPath.Child("runtimeProvider"))...)
+	}
+	if build.Timeout != nil && build.Timeout.Duration <= 0 {
+		errs = append(errs, field.Invalid(buildPath.Child("timeout"), build.Timeout.Duration.String(), "must be greater than zero"))
+	}
+	if address := build.Registry.Address; address != "" {
+		if strings.Contains(address, "://") {
+			errs = append(errs, field.Invalid(buildPath.Child("registry", "address"), address, "must not contain a scheme"))
+		} else if strings.ContainsAny(address, " \t\n") {
+			errs = append(errs, field.Invalid(buildPath.Child("registry", "address"), address, "must not contain whitespaces"))
+		}
+	}
+	if build.Registry.Secret != "" {
+		errs = append(errs, validateDNS1123Subdomain(build.Registry.Secret, buildPath.Child("registry", "secret"))...)
+	}
+	if build.Registry.CA != "" {
+		errs = append(errs, validateDNS1123Subdomain(build.Registry.CA, buildPath.Child("registry", "ca"))...)
+	}
+
+	return errs
+}
+
+func (in *Placement) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if in.Name == "" {
+		errs = append(errs, field.Required(path.Child("metadata", "name"), ""))
+	} else {
+		errs = append(errs, validateDNS1123Subdomain(in.Name, path.Child("metadata", "name"))...)
+	}
+
+	specPath := path.Child("spec")
+	if in.Spec.LocationWorkspace == "" {
+		errs = append(errs, field.Required(specPath.Child("locationWorkspace"), ""))
+	} else {
+		errs = append(errs, validateLogicalClusterPath(in.Spec.LocationWorkspace, specPath.Child("locationWorkspace"))...)
+	}
+	if in.Spec.LocationResource.Version == "" {
+		errs = append(errs, field.Required(specPath.Child("locationResource", "version"), ""))
+	}
+	if in.Spec.LocationResource.Resource == "" {
+		errs = append(errs, field.Required(specPath.Child("locationResource", "resource"), ""))
+	}
+	for i := range in.Spec.LocationSelectors {
+		errs = append(errs, metav1validation.ValidateLabelSelector(&in.Spec.LocationSelectors[i], specPath.Child("locationSelectors").Index(i))...)
+	}
+	if in.Spec.NamespaceSelector != nil {
+		errs = append(errs, metav1validation.ValidateLabelSelector(in.Spec.NamespaceSelector, specPath.Child("namespaceSelector"))...)
+	}
+
+	return errs
+}
+
+func (in *KaotoSpec) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, in.UI.validate(path.Child("ui"))...)
+	errs = append(errs, in.Backend.validate(path.Child("backend"))...)
+	errs = append(errs, metav1validation.ValidateLabels(in.NodeSelector, path.Child("nodeSelector"))...)
+	for i, secret := range in.ImagePullSecrets {
+		if secret.Name == "" {
+			errs = append(errs, field.Required(path.Child("imagePullSecrets").Index(i).Child("name"), ""))
+		} else {
+			errs = append(errs, validateDNS1123Subdomain(secret.Name, path.Child("imagePullSecrets").Index(i).Child("name"))...)
+		}
+	}
+	errs = append(errs, in.Ingress.validate(path.Child("ingress"))...)
+
+	return errs
+}
+
+func (in *KaotoComponent) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if strings.ContainsAny(in.Image, " \t\n") {
+		errs = append(errs, field.Invalid(path.Child("image"), in.Image, "must not contain whitespaces"))
+	}
+	if in.ImagePullPolicy != "" {
+		errs = append(errs, validateEnum(string(in.ImagePullPolicy), imagePullPolicies, path.Child("imagePullPolicy"))...)
+	}
+	if in.Replicas != nil && *in.Replicas < 0 {
+		errs = append(errs, field.Invalid(path.Child("replicas"), *in.Replicas, "must be greater than or equal to 0"))
+	}
+	for name, quantity := range in.Resources.Limits {
+		if quantity.Sign() < 0 {
+			errs = append(errs, field.Invalid(path.Child("resources", "limits").Key(string(name)), quantity.String(), "must be greater than or equal to 0"))
+		}
+	}
+	for name, quantity := range in.Resources.Requests {
+		if quantity.Sign() < 0 {
+			errs = append(errs, field.Invalid(path.Child("resources", "requests").Key(string(name)), quantity.String(), "must be greater than or equal to 0"))
+		}
+		if limit, ok := in.Resources.Limits[name]; ok && quantity.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(path.Child("resources", "requests").Key(string(name)), quantity.String(), "must be less than or equal to the limit"))
+		}
+	}
+	names := sets.NewString()
+	for i, env := range in.Env {
+		envPath := path.Child("env").Index(i)
+		if env.Name == "" {
+			errs = append(errs, field.Required(envPath.Child("name"), ""))
+			continue
+		}
+		for _, msg := range validation.IsEnvVarName(env.Name) {
+			errs = append(errs, field.Invalid(envPath.Child("name"), env.Name, msg))
+		}
+		if names.Has(env.Name) {
+			errs = append(errs, field.Duplicate(envPath.Child("name"), env.Name))
+		}
+		names.Insert(env.Name)
+		if env.Value != "" && env.ValueFrom != nil {
+			errs = append(errs, field.Invalid(envPath.Child("valueFrom"), "", "may not be specified when `value` is not empty"))
+		}
+	}
+
+	return errs
+}
+
+func (in *KaotoIngress) validate(path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if in.Profile != "" {
+		errs = append(errs, validateEnum(string(in.Profile), ingressProfiles, path.Child("profile"))...)
+	}
+	if in.IngressClassName != nil {
+		errs = append(errs, validateDNS1123Subdomain(*in.IngressClassName, path.Child("ingressClassName"))...)
+	}
+	for key := range in.Annotations {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(key)) {
+			errs = append(errs, field.Invalid(path.Child("annotations"), key, msg))
+		}
+	}
+	if in.Host != "" {
+		errs = append(errs, validateDNS1123Subdomain(in.Host, path.Child("host"))...)
+	} else if in.Profile == IngressProfileGatewayHTTPRoute {
+		errs = append(errs, field.Required(path.Child("host"), "required by the "+string(IngressProfileGatewayHTTPRoute)+" profile"))
+	}
+	if in.TLS != nil && in.TLS.SecretName != "" {
+		errs = append(errs, validateDNS1123Subdomain(in.TLS.SecretName, path.Child("tls", "secretName"))...)
+	}
+	if in.Gateway != nil {
+		gatewayPath := path.Child("gateway")
+		if in.Gateway.Name == "" {
+			errs = append(errs, field.Required(gatewayPath.Child("name"), ""))
+		} else {
+			errs = append(errs, validateDNS1123Subdomain(in.Gateway.Name, gatewayPath.Child("name"))...)
+		}
+		if in.Gateway.Namespace != "" {
+			errs = append(errs, validateDNS1123Label(in.Gateway.Namespace, gatewayPath.Child("namespace"))...)
+		}
+		if in.Gateway.SectionName != "" {
+			errs = append(errs, validateDNS1123Subdomain(in.Gateway.SectionName, gatewayPath.Child("sectionName"))...)
+		}
+	} else if in.Profile == IngressProfileGatewayHTTPRoute {
+		errs = append(errs, field.Required(path.Child("gateway"), "required by the "+string(IngressProfileGatewayHTTPRoute)+" profile"))
+	}
+
+	return errs
+}
+
+// ValidateUpdate validates the configuration can be reloaded in place of the old one.
+// The fields that configure the managers, rather than the reconciliation of the APIBindings,
+// are only read on startup, and cannot be changed.
+func (in *ServiceConfiguration) ValidateUpdate(old *ServiceConfiguration) field.ErrorList {
+	var errs field.ErrorList
+
+	// The controller manager configuration is only read on startup
+	mgrCfg, oldMgrCfg := &in.ControllerManagerConfigurationSpec, &old.ControllerManagerConfigurationSpec
+	if !equality.Semantic.DeepEqual(mgrCfg.LeaderElection, oldMgrCfg.LeaderElection) {
+		errs = append(errs, field.Forbidden(field.NewPath("leaderElection"), restartRequired))
+	}
+	if !equality.Semantic.DeepEqual(mgrCfg.Health, oldMgrCfg.Health) {
+		errs = append(errs, field.Forbidden(field.NewPath("health"), restartRequired))
+	}
+	if !equality.Semantic.DeepEqual(mgrCfg.Metrics, oldMgrCfg.Metrics) {
+		errs = append(errs, field.Forbidden(field.NewPath("metrics"), restartRequired))
+	}
+	if !equality.Semantic.DeepEqual(mgrCfg.Webhook, oldMgrCfg.Webhook) {
+		errs = append(errs, field.Forbidden(field.NewPath("webhook"), restartRequired))
+	}
+	if !equality.Semantic.DeepEqual(mgrCfg.SyncPeriod, oldMgrCfg.SyncPeriod) {
+		errs = append(errs, field.Forbidden(field.NewPath("syncPeriod"), restartRequired))
+	}
+	if mgrCfg.CacheNamespace != oldMgrCfg.CacheNamespace {
+		errs = append(errs, field.Forbidden(field.NewPath("cacheNamespace"), restartRequired))
+	}
+	if !equality.Semantic.DeepEqual(mgrCfg.GracefulShutdownTimeout, oldMgrCfg.GracefulShutdownTimeout) {
+		errs = append(errs, field.Forbidden(field.NewPath("gracefulShutDown"), restartRequired))
+	}
+	if !equality.Semantic.DeepEqual(mgrCfg.Controller, oldMgrCfg.Controller) {
+		errs = append(errs, field.Forbidden(field.NewPath("controller"), restartRequired))
+	}
+
+	servicePath := field.NewPath("service")
+	if in.Service.StatusNamespace != old.Service.StatusNamespace {
+		errs = append(errs, field.Forbidden(servicePath.Child("statusNamespace"), restartRequired))
+	}
+	if in.Service.OperatorImage != old.Service.OperatorImage {
+		errs = append(errs, field.Forbidden(servicePath.Child("operatorImage"), restartRequired))
+	}
+	if !equality.Semantic.DeepEqual(in.Service.Initializer, old.Service.Initializer) {
+		errs = append(errs, field.Forbidden(servicePath.Child("initializer"), restartRequired))
+	}
+	if in.Service.Metrics.MaxLogicalClustersOrDefault() != old.Service.Metrics.MaxLogicalClustersOrDefault() {
+		errs = append(errs, field.Forbidden(servicePath.Child("metrics", "maxLogicalClusters"), restartRequired))
+	}
+
+	camelKPath := servicePath.Child("apiExports", "camel-k")
+	errs = append(errs, in.Service.APIExports.CamelK.LocalAPIExportReference.validateUpdate(
+		&old.Service.APIExports.CamelK.LocalAPIExportReference, camelKPath)...)
+
+	kaotoPath := servicePath.Child("apiExports", "kaoto")
+	errs = append(errs, in.Service.APIExports.Kaoto.LocalAPIExportReference.validateUpdate(
+		&old.Service.APIExports.Kaoto.LocalAPIExportReference, kaotoPath)...)
+	if in.Service.APIExports.Kaoto.OnAPIBinding.Kaoto.Ingress.ProfileOrDefault() != old.Service.APIExports.Kaoto.OnAPIBinding.Kaoto.Ingress.ProfileOrDefault() {
+		errs = append(errs, field.Forbidden(kaotoPath.Child("onApiBinding", "kaoto", "ingress", "profile"), restartRequired))
+	}
+
+	// The add-on APIExports can neither be added nor removed, as each one is used to configure a manager
+	addOnsPath := servicePath.Child("apiExports", "addOns")
+	if len(in.Service.APIExports.AddOns) != len(old.Service.APIExports.AddOns) {
+		errs = append(errs, field.Forbidden(addOnsPath, restartRequired))
+	} else {
+		for i := range in.Service.APIExports.AddOns {
+			errs = append(errs, in.Service.APIExports.AddOns[i].LocalAPIExportReference.validateUpdate(
+				&old.Service.APIExports.AddOns[i].LocalAPIExportReference, addOnsPath.Index(i))...)
+		}
+	}
+
+	return errs
+}
+
+func (in *LocalAPIExportReference) validateUpdate(old *LocalAPIExportReference, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if in.APIExportName != old.APIExportName {
+		errs = append(errs, field.Forbidden(path.Child("apiExportName"), restartRequired))
+	}
+	if in.EndpointSliceName() != old.EndpointSliceName() {
+		errs = append(errs, field.Forbidden(path.Child("apiExportEndpointSliceName"), restartRequired))
+	}
+	return errs
+}
+
+func validateEnum(value string, values []string, path *field.Path) field.ErrorList {
+	for _, v := range values {
+		if value == v {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(path, value, values)}
+}
+
+func enumValues[T ~string](values []T) []string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		s = append(s, string(v))
+	}
+	return s
+}
+
+func validateLogicalClusterPath(value string, path *field.Path) field.ErrorList {
+	for _, segment := range strings.Split(value, ":") {
+		if msgs := validation.IsDNS1123Label(segment); len(msgs) > 0 {
+			return field.ErrorList{field.Invalid(path, value, "must be a logical cluster path, e.g., root:org:ws")}
+		}
+	}
+	return nil
+}
+
+func validateDNS1123Label(value string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Label(value) {
+		errs = append(errs, field.Invalid(path, value, msg))
+	}
+	return errs
+}
+
+func validateDNS1123Subdomain(value string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, msg := range validation.IsDNS1123Subdomain(value) {
+		errs = append(errs, field.Invalid(path, value, msg))
+	}
+	return errs
+}
@@ -0,0 +1,834 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/pointer"
+
+	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
+
+	v1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+func validServiceConfiguration() *ServiceConfiguration {
+	return &ServiceConfiguration{
+		Service: ServiceConfigurationSpec{
+			StatusNamespace: "camel-kcp",
+			APIExports: APIExports{
+				CamelK: CamelKAPIExport{
+					LocalAPIExportReference: LocalAPIExportReference{
+						APIExportName: "camel-k",
+					},
+					OnAPIBinding: OnCamelKAPIBinding{
+						DefaultPlatform: &IntegrationPlatform{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "camel-k",
+								Namespace: "camel-k",
+							},
+							Spec: v1.IntegrationPlatformSpec{
+								Cluster: v1.IntegrationPlatformClusterKubernetes,
+								Profile: v1.TraitProfileKubernetes,
+								Build: v1.IntegrationPlatformBuildSpec{
+									BuildStrategy:   v1.BuildStrategyRoutine,
+									PublishStrategy: v1.IntegrationPlatformBuildPublishStrategySpectrum,
+									RuntimeProvider: v1.RuntimeProviderQuarkus,
+									Timeout:         &metav1.Duration{Duration: 5 * time.Minute},
+									Registry: v1.RegistrySpec{
+										Address: "registry.example.com:5000",
+										Secret:  "registry-credentials",
+									},
+								},
+							},
+						},
+						DefaultPlacement: validPlacement("default"),
+					},
+				},
+				Kaoto: KaotoAPIExport{
+					LocalAPIExportReference: LocalAPIExportReference{
+						APIExportName: "kaoto",
+					},
+					OnAPIBinding: OnKaotoAPIBinding{
+						DefaultPlacement: validPlacement("kaoto"),
+						Kaoto: KaotoSpec{
+							UI: KaotoComponent{
+								Image:           "kaoto/ui:latest",
+								ImagePullPolicy: corev1.PullIfNotPresent,
+								Replicas:        pointer.Int32(1),
+								Resources: corev1.ResourceRequirements{
+									Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+									Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+								},
+								Env: []corev1.EnvVar{{Name: "KAOTO_API", Value: "/api"}},
+							},
+							NodeSelector:     map[string]string{"kubernetes.io/os": "linux"},
+							ImagePullSecrets: []corev1.LocalObjectReference{{Name: "pull-secret"}},
+							Ingress: KaotoIngress{
+								Profile:          IngressProfileNginx,
+								IngressClassName: pointer.String("nginx"),
+								Annotations:      map[string]string{"nginx.ingress.kubernetes.io/rewrite-target": "/"},
+								Host:             "kaoto.example.com",
+								TLS:              &KaotoIngressTLS{SecretName: "kaoto-tls"},
+							},
+						},
+					},
+					OnAPIUnbinding: OnAPIUnbinding{
+						CleanupPolicy:   CleanupPolicyDelete,
+						CleanupPolicies: map[string]CleanupPolicy{"Namespace": CleanupPolicyRetain},
+					},
+				},
+				AddOns: []AddOnAPIExport{
+					{
+						LocalAPIExportReference: LocalAPIExportReference{
+							APIExportName: "hawtio",
+						},
+						OnAPIBinding: OnAddOnAPIBinding{
+							DefaultPlacement: validPlacement("hawtio"),
+							Manifests: []runtime.RawExtension{
+								{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"hawtio"}}`)},
+								{Raw: []byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"hawtio","namespace":"hawtio"}}`)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validPlacement(name string) *Placement {
+	return &Placement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: schedulingv1alpha1.PlacementSpec{
+			LocationWorkspace: "root:camel-kcp",
+			LocationResource: schedulingv1alpha1.GroupVersionResource{
+				Group:    "workload.kcp.io",
+				Version:  "v1alpha1",
+				Resource: "synctargets",
+			},
+			LocationSelectors: []metav1.LabelSelector{
+				{MatchLabels: map[string]string{"org.apache.camel/data-plane": "true"}},
+			},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "kubernetes.io/metadata.name", Operator: metav1.LabelSelectorOpIn, Values: []string{name}},
+				},
+			},
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	camelK := func(c *ServiceConfiguration) *CamelKAPIExport { return &c.Service.APIExports.CamelK }
+	kaoto := func(c *ServiceConfiguration) *KaotoAPIExport { return &c.Service.APIExports.Kaoto }
+	platform := func(c *ServiceConfiguration) *IntegrationPlatform { return camelK(c).OnAPIBinding.DefaultPlatform }
+	placement := func(c *ServiceConfiguration) *Placement { return camelK(c).OnAPIBinding.DefaultPlacement }
+	kaotoSpec := func(c *ServiceConfiguration) *KaotoSpec { return &kaoto(c).OnAPIBinding.Kaoto }
+	addOn := func(c *ServiceConfiguration) *AddOnAPIExport { return &c.Service.APIExports.AddOns[0] }
+
+	tests := []struct {
+		name   string
+		mutate func(*ServiceConfiguration)
+		errors []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(*ServiceConfiguration) {},
+		},
+		{
+			name: "minimal",
+			mutate: func(c *ServiceConfiguration) {
+				*c = ServiceConfiguration{}
+				camelK(c).APIExportName = "camel-k"
+				kaoto(c).APIExportName = "kaoto"
+			},
+		},
+		{
+			name:   "invalid max logical clusters",
+			mutate: func(c *ServiceConfiguration) { c.Service.Metrics.MaxLogicalClusters = pointer.Int32(-1) },
+			errors: []string{"service.metrics.maxLogicalClusters"},
+		},
+		{
+			name:   "invalid status namespace",
+			mutate: func(c *ServiceConfiguration) { c.Service.StatusNamespace = "Camel.KCP" },
+			errors: []string{"service.statusNamespace"},
+		},
+		{
+			name:   "invalid operator image",
+			mutate: func(c *ServiceConfiguration) { c.Service.OperatorImage = "camel-kcp latest" },
+			errors: []string{"service.operatorImage"},
+		},
+		{
+			name: "initializer",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.Initializer = &Initializer{WorkspaceTypes: []WorkspaceTypeReference{
+					{Path: "root", Name: "camel"},
+					{Path: "root:org", Name: "camel"},
+				}}
+			},
+		},
+		{
+			name: "invalid initializer WorkspaceTypes",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.Initializer = &Initializer{WorkspaceTypes: []WorkspaceTypeReference{
+					{Path: "root", Name: "camel"},
+					{Path: "root", Name: ""},
+					{Path: "root", Name: "Camel_K"},
+					{Path: "root:Org", Name: "camel-k"},
+					{Path: "root", Name: "camel"},
+				}}
+			},
+			errors: []string{
+				"service.initializer.workspaceTypes[1].name",
+				"service.initializer.workspaceTypes[2].name",
+				"service.initializer.workspaceTypes[3].path",
+				"service.initializer.workspaceTypes[4]",
+			},
+		},
+		{
+			name:   "missing Camel K APIExport name",
+			mutate: func(c *ServiceConfiguration) { camelK(c).APIExportName = "" },
+			errors: []string{"service.apiExports.camel-k.apiExportName"},
+		},
+		{
+			name:   "invalid Kaoto APIExport name",
+			mutate: func(c *ServiceConfiguration) { kaoto(c).APIExportName = "Kaoto" },
+			errors: []string{"service.apiExports.kaoto.apiExportName"},
+		},
+		{
+			name:   "invalid APIExportEndpointSlice name",
+			mutate: func(c *ServiceConfiguration) { camelK(c).APIExportEndpointSliceName = "camel_k" },
+			errors: []string{"service.apiExports.camel-k.apiExportEndpointSliceName"},
+		},
+		{
+			name:   "invalid cleanup policy",
+			mutate: func(c *ServiceConfiguration) { camelK(c).OnAPIUnbinding.CleanupPolicy = "Orphan" },
+			errors: []string{"service.apiExports.camel-k.onApiUnbinding.cleanupPolicy"},
+		},
+		{
+			name: "invalid cleanup policy per kind",
+			mutate: func(c *ServiceConfiguration) {
+				kaoto(c).OnAPIUnbinding.CleanupPolicies = map[string]CleanupPolicy{"Deployment": "Keep"}
+			},
+			errors: []string{"service.apiExports.kaoto.onApiUnbinding.cleanupPolicies[Deployment]"},
+		},
+		{
+			name: "empty cleanup policy kind",
+			mutate: func(c *ServiceConfiguration) {
+				kaoto(c).OnAPIUnbinding.CleanupPolicies = map[string]CleanupPolicy{"": CleanupPolicyRetain}
+			},
+			errors: []string{"service.apiExports.kaoto.onApiUnbinding.cleanupPolicies"},
+		},
+		{
+			name:   "invalid platform name",
+			mutate: func(c *ServiceConfiguration) { platform(c).Name = "Camel K" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.metadata.name"},
+		},
+		{
+			name:   "invalid platform namespace",
+			mutate: func(c *ServiceConfiguration) { platform(c).Namespace = "camel.k" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.metadata.namespace"},
+		},
+		{
+			name:   "invalid platform cluster",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Cluster = "Nomad" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.cluster"},
+		},
+		{
+			name:   "invalid platform profile",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Profile = "Serverless" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.profile"},
+		},
+		{
+			name:   "invalid build strategy",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Build.BuildStrategy = "job" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.build.buildStrategy"},
+		},
+		{
+			name:   "invalid publish strategy",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Build.PublishStrategy = "Docker" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.build.publishStrategy"},
+		},
+		{
+			name:   "invalid runtime provider",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Build.RuntimeProvider = "spring-boot" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.build.runtimeProvider"},
+		},
+		{
+			name:   "invalid build timeout",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Build.Timeout = &metav1.Duration{} },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.build.timeout"},
+		},
+		{
+			name: "registry address with scheme",
+			mutate: func(c *ServiceConfiguration) {
+				platform(c).Spec.Build.Registry.Address = "https://registry.example.com"
+			},
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.build.registry.address"},
+		},
+		{
+			name:   "registry address with whitespaces",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Build.Registry.Address = "registry example" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.build.registry.address"},
+		},
+		{
+			name:   "invalid registry secret",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Build.Registry.Secret = "Registry Secret" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.build.registry.secret"},
+		},
+		{
+			name:   "invalid registry CA",
+			mutate: func(c *ServiceConfiguration) { platform(c).Spec.Build.Registry.CA = "CA" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlatform.spec.build.registry.ca"},
+		},
+		{
+			name:   "missing placement name",
+			mutate: func(c *ServiceConfiguration) { placement(c).Name = "" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlacement.metadata.name"},
+		},
+		{
+			name:   "invalid placement name",
+			mutate: func(c *ServiceConfiguration) { kaoto(c).OnAPIBinding.DefaultPlacement.Name = "Kaoto" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.createDefaultPlacement.metadata.name"},
+		},
+		{
+			name:   "missing placement location workspace",
+			mutate: func(c *ServiceConfiguration) { placement(c).Spec.LocationWorkspace = "" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlacement.spec.locationWorkspace"},
+		},
+		{
+			name:   "invalid placement location workspace",
+			mutate: func(c *ServiceConfiguration) { placement(c).Spec.LocationWorkspace = "root::camel-kcp" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlacement.spec.locationWorkspace"},
+		},
+		{
+			name:   "missing placement location resource version",
+			mutate: func(c *ServiceConfiguration) { placement(c).Spec.LocationResource.Version = "" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlacement.spec.locationResource.version"},
+		},
+		{
+			name:   "missing placement location resource",
+			mutate: func(c *ServiceConfiguration) { placement(c).Spec.LocationResource.Resource = "" },
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlacement.spec.locationResource.resource"},
+		},
+		{
+			name: "invalid placement location selector",
+			mutate: func(c *ServiceConfiguration) {
+				placement(c).Spec.LocationSelectors[0].MatchExpressions = []metav1.LabelSelectorRequirement{
+					{Key: "data-plane", Operator: metav1.LabelSelectorOpIn},
+				}
+			},
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlacement.spec.locationSelectors[0].matchExpressions[0].values"},
+		},
+		{
+			name: "invalid placement namespace selector",
+			mutate: func(c *ServiceConfiguration) {
+				placement(c).Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"name": "not valid"}}
+			},
+			errors: []string{"service.apiExports.camel-k.onApiBinding.createDefaultPlacement.spec.namespaceSelector.matchLabels"},
+		},
+		{
+			name: "valid override",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{{
+					Name:            "team-a",
+					LogicalClusters: []string{"team-a"},
+					Platform:        &runtime.RawExtension{Raw: []byte(`{"spec":{"build":{"registry":{"address":"registry.team-a.example.com"}}}}`)},
+					Placement:       &runtime.RawExtension{Raw: []byte(`{"spec":{"locationWorkspace":"root:team-a"}}`)},
+				}}
+			},
+		},
+		{
+			name: "valid workspace paths override",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{{
+					Name:           "org-a",
+					WorkspacePaths: []string{"root:org-a"},
+					Platform:       &runtime.RawExtension{Raw: []byte(`{"spec":{"build":{"registry":{"organization":"org-a"}}}}`)},
+				}}
+			},
+		},
+		{
+			name: "missing override name and logical clusters",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{{}}
+			},
+			errors: []string{
+				"service.apiExports.camel-k.onApiBinding.overrides[0].name",
+				"service.apiExports.camel-k.onApiBinding.overrides[0].logicalClusters",
+			},
+		},
+		{
+			name: "duplicate override name",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{
+					{Name: "secure", LogicalClusters: []string{"secure"}},
+					{Name: "secure", LogicalClusters: []string{"other"}},
+				}
+			},
+			errors: []string{"service.apiExports.camel-k.onApiBinding.overrides[1].name"},
+		},
+		{
+			name: "invalid override logical cluster",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{{
+					Name:            "team-a",
+					LogicalClusters: []string{""},
+				}}
+			},
+			errors: []string{
+				"service.apiExports.camel-k.onApiBinding.overrides[0].logicalClusters[0]",
+			},
+		},
+		{
+			name: "invalid override workspace paths",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{{
+					Name:           "org-a",
+					WorkspacePaths: []string{"", "root:Org-A"},
+				}}
+			},
+			errors: []string{
+				"service.apiExports.camel-k.onApiBinding.overrides[0].workspacePaths[0]",
+				"service.apiExports.camel-k.onApiBinding.overrides[0].workspacePaths[1]",
+			},
+		},
+		{
+			name: "invalid override platform",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{{
+					Name:            "secure",
+					LogicalClusters: []string{"secure"},
+					Platform:        &runtime.RawExtension{Raw: []byte(`{"metadata":{"name":"other"},"spec":{"cluster":"Nomad"}}`)},
+				}}
+			},
+			errors: []string{
+				"service.apiExports.camel-k.onApiBinding.overrides[0].platform.metadata.name",
+				"service.apiExports.camel-k.onApiBinding.overrides[0].platform.spec.cluster",
+			},
+		},
+		{
+			name: "invalid override placement",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{{
+					Name:            "secure",
+					LogicalClusters: []string{"secure"},
+					Placement:       &runtime.RawExtension{Raw: []byte(`{"metadata":{"name":"other"},"spec":{"locationResource":{"version":null}}}`)},
+				}}
+			},
+			errors: []string{
+				"service.apiExports.camel-k.onApiBinding.overrides[0].placement.metadata.name",
+				"service.apiExports.camel-k.onApiBinding.overrides[0].placement.spec.locationResource.version",
+			},
+		},
+		{
+			name: "malformed override",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.Overrides = []CamelKOverride{{
+					Name:            "secure",
+					LogicalClusters: []string{"secure"},
+					Platform:        &runtime.RawExtension{Raw: []byte(`{"spec":{"build":"routine"}}`)},
+				}}
+			},
+			errors: []string{"service.apiExports.camel-k.onApiBinding.overrides[0].platform"},
+		},
+		{
+			name: "valid registry provisioning",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.ProvisionRegistry = &RegistryProvisioning{
+					OrganizationPrefix: "tenants/",
+					CredentialsSecret:  SecretReference{Namespace: "camel-kcp", Name: "registry"},
+					SecretName:         "registry",
+				}
+			},
+		},
+		{
+			name: "invalid registry provisioning",
+			mutate: func(c *ServiceConfiguration) {
+				camelK(c).OnAPIBinding.ProvisionRegistry = &RegistryProvisioning{
+					OrganizationPrefix: "Tenants//",
+					CredentialsSecret:  SecretReference{Namespace: "Camel KCP"},
+					SecretName:         "Registry",
+				}
+			},
+			errors: []string{
+				"service.apiExports.camel-k.onApiBinding.provisionRegistry.organizationPrefix",
+				"service.apiExports.camel-k.onApiBinding.provisionRegistry.credentialsSecret.name",
+				"service.apiExports.camel-k.onApiBinding.provisionRegistry.credentialsSecret.namespace",
+				"service.apiExports.camel-k.onApiBinding.provisionRegistry.secretName",
+			},
+		},
+		{
+			name:   "invalid Kaoto image",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).UI.Image = "kaoto ui" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ui.image"},
+		},
+		{
+			name:   "invalid Kaoto image pull policy",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).Backend.ImagePullPolicy = "Sometimes" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.backend.imagePullPolicy"},
+		},
+		{
+			name:   "negative Kaoto replicas",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).Backend.Replicas = pointer.Int32(-1) },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.backend.replicas"},
+		},
+		{
+			name: "negative Kaoto resource limit",
+			mutate: func(c *ServiceConfiguration) {
+				kaotoSpec(c).Backend.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("-1")}
+			},
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.backend.resources.limits[cpu]"},
+		},
+		{
+			name: "Kaoto resource request greater than limit",
+			mutate: func(c *ServiceConfiguration) {
+				kaotoSpec(c).UI.Resources.Requests[corev1.ResourceMemory] = resource.MustParse("1Gi")
+			},
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ui.resources.requests[memory]"},
+		},
+		{
+			name:   "missing Kaoto env name",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).UI.Env[0].Name = "" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ui.env[0].name"},
+		},
+		{
+			name:   "invalid Kaoto env name",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).UI.Env[0].Name = "1KAOTO" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ui.env[0].name"},
+		},
+		{
+			name: "duplicate Kaoto env name",
+			mutate: func(c *ServiceConfiguration) {
+				kaotoSpec(c).UI.Env = append(kaotoSpec(c).UI.Env, corev1.EnvVar{Name: "KAOTO_API"})
+			},
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ui.env[1].name"},
+		},
+		{
+			name: "Kaoto env with value and value from",
+			mutate: func(c *ServiceConfiguration) {
+				kaotoSpec(c).UI.Env[0].ValueFrom = &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}
+			},
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ui.env[0].valueFrom"},
+		},
+		{
+			name: "invalid Kaoto node selector",
+			mutate: func(c *ServiceConfiguration) {
+				kaotoSpec(c).NodeSelector = map[string]string{"kubernetes.io/os": "not linux"}
+			},
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.nodeSelector"},
+		},
+		{
+			name:   "missing Kaoto image pull secret name",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).ImagePullSecrets[0].Name = "" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.imagePullSecrets[0].name"},
+		},
+		{
+			name:   "invalid Kaoto ingress profile",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).Ingress.Profile = "Traefik" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ingress.profile"},
+		},
+		{
+			name:   "invalid Kaoto ingress class name",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).Ingress.IngressClassName = pointer.String("") },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ingress.ingressClassName"},
+		},
+		{
+			name:   "invalid Kaoto ingress annotation",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).Ingress.Annotations = map[string]string{"-invalid": ""} },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ingress.annotations"},
+		},
+		{
+			name:   "invalid Kaoto ingress host",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).Ingress.Host = "https://kaoto.example.com" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ingress.host"},
+		},
+		{
+			name:   "invalid Kaoto ingress TLS secret name",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).Ingress.TLS.SecretName = "Kaoto TLS" },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ingress.tls.secretName"},
+		},
+		{
+			name:   "missing Kaoto ingress gateway",
+			mutate: func(c *ServiceConfiguration) { kaotoSpec(c).Ingress.Profile = IngressProfileGatewayHTTPRoute },
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ingress.gateway"},
+		},
+		{
+			name: "missing Kaoto ingress host",
+			mutate: func(c *ServiceConfiguration) {
+				kaotoSpec(c).Ingress.Profile = IngressProfileGatewayHTTPRoute
+				kaotoSpec(c).Ingress.Gateway = &GatewayReference{Name: "gateway"}
+				kaotoSpec(c).Ingress.Host = ""
+			},
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ingress.host"},
+		},
+		{
+			name: "invalid Kaoto ingress gateway",
+			mutate: func(c *ServiceConfiguration) {
+				kaotoSpec(c).Ingress.Profile = IngressProfileGatewayHTTPRoute
+				kaotoSpec(c).Ingress.Gateway = &GatewayReference{Namespace: "Gateways", SectionName: "HTTPS"}
+			},
+			errors: []string{
+				"service.apiExports.kaoto.onApiBinding.kaoto.ingress.gateway.name",
+				"service.apiExports.kaoto.onApiBinding.kaoto.ingress.gateway.namespace",
+				"service.apiExports.kaoto.onApiBinding.kaoto.ingress.gateway.sectionName",
+			},
+		},
+		{
+			name:   "missing add-on APIExport name",
+			mutate: func(c *ServiceConfiguration) { addOn(c).APIExportName = "" },
+			errors: []string{"service.apiExports.addOns[0].apiExportName"},
+		},
+		{
+			name:   "too long add-on APIExport name",
+			mutate: func(c *ServiceConfiguration) { addOn(c).APIExportName = strings.Repeat("a", 64) },
+			errors: []string{"service.apiExports.addOns[0].apiExportName"},
+		},
+		{
+			name:   "duplicate add-on APIExport name",
+			mutate: func(c *ServiceConfiguration) { addOn(c).APIExportName = "kaoto" },
+			errors: []string{"service.apiExports.addOns[0].apiExportName"},
+		},
+		{
+			name:   "invalid add-on placement",
+			mutate: func(c *ServiceConfiguration) { addOn(c).OnAPIBinding.DefaultPlacement.Name = "" },
+			errors: []string{"service.apiExports.addOns[0].onApiBinding.createDefaultPlacement.metadata.name"},
+		},
+		{
+			name:   "add-on placement of Camel K",
+			mutate: func(c *ServiceConfiguration) { addOn(c).OnAPIBinding.DefaultPlacement.Name = "default" },
+			errors: []string{"service.apiExports.addOns[0].onApiBinding.createDefaultPlacement.metadata.name"},
+		},
+		{
+			name:   "add-on placement of Kaoto",
+			mutate: func(c *ServiceConfiguration) { addOn(c).OnAPIBinding.DefaultPlacement.Name = "kaoto" },
+			errors: []string{"service.apiExports.addOns[0].onApiBinding.createDefaultPlacement.metadata.name"},
+		},
+		{
+			name: "invalid add-on manifests",
+			mutate: func(c *ServiceConfiguration) {
+				addOn(c).OnAPIBinding.Manifests = []runtime.RawExtension{
+					{Raw: []byte(`{"kind":"ConfigMap","metadata":{"name":"hawtio"}}`)},
+					{Raw: []byte(`{"apiVersion":"v1","metadata":{"name":"hawtio"}}`)},
+					{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap"}`)},
+					{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMapList","items":[]}`)},
+					{Raw: []byte(`{`)},
+				}
+			},
+			errors: []string{
+				"service.apiExports.addOns[0].onApiBinding.manifests[0]",
+				"service.apiExports.addOns[0].onApiBinding.manifests[1]",
+				"service.apiExports.addOns[0].onApiBinding.manifests[2]",
+				"service.apiExports.addOns[0].onApiBinding.manifests[3]",
+				"service.apiExports.addOns[0].onApiBinding.manifests[4]",
+			},
+		},
+		{
+			name: "duplicate add-on manifest",
+			mutate: func(c *ServiceConfiguration) {
+				addOn(c).OnAPIBinding.Manifests = append(addOn(c).OnAPIBinding.Manifests,
+					runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"hawtio","labels":{"app":"hawtio"}}}`)})
+			},
+			errors: []string{"service.apiExports.addOns[0].onApiBinding.manifests[2]"},
+		},
+		{
+			name: "templated manifests",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.APIExports.CamelK.OnAPIBinding.Manifests = []runtime.RawExtension{
+					{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .LogicalCluster }}","namespace":"camel-k"},"data":{"{{ .WorkspacePath }}":"https://{{ .IngressHost }}"}}`)},
+				}
+				c.Service.APIExports.Kaoto.OnAPIBinding.Manifests = []runtime.RawExtension{
+					{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"kaoto-{{ .LogicalCluster }}","namespace":"kaoto"}}`)},
+				}
+			},
+		},
+		{
+			name: "invalid manifest templates",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.APIExports.CamelK.OnAPIBinding.Manifests = []runtime.RawExtension{
+					{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .Unknown }}"}}`)},
+				}
+				c.Service.APIExports.Kaoto.OnAPIBinding.Manifests = []runtime.RawExtension{
+					{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .LogicalCluster"}}`)},
+				}
+			},
+			errors: []string{
+				"service.apiExports.camel-k.onApiBinding.manifests[0]",
+				"service.apiExports.kaoto.onApiBinding.manifests[0]",
+			},
+		},
+		{
+			name:   "invalid ingress host",
+			mutate: func(c *ServiceConfiguration) { c.Service.IngressHost = "Example_com" },
+			errors: []string{"service.ingressHost"},
+		},
+		{
+			name:   "invalid add-on cleanup policy",
+			mutate: func(c *ServiceConfiguration) { addOn(c).OnAPIUnbinding.CleanupPolicy = "Orphan" },
+			errors: []string{"service.apiExports.addOns[0].onApiUnbinding.cleanupPolicy"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cfg := validServiceConfiguration()
+			test.mutate(cfg)
+
+			g.Expect(fieldPaths(cfg.Validate())).To(ConsistOf(test.errors))
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*ServiceConfiguration)
+		errors []string
+	}{
+		{
+			name: "reconciliation fields",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.APIExports.Kaoto.OnAPIBinding.Kaoto.UI.Replicas = pointer.Int32(2)
+				c.Service.APIExports.CamelK.OnAPIBinding.DefaultPlacement.Spec.LocationWorkspace = "root:other"
+				c.Service.APIExports.CamelK.OnAPIBinding.DefaultPlatform.Namespace = "other"
+				c.Service.APIExports.AddOns[0].OnAPIBinding.Manifests = nil
+				c.Service.IngressHost = "apps.example.com"
+			},
+		},
+		{
+			name: "leader election",
+			mutate: func(c *ServiceConfiguration) {
+				c.LeaderElection = &configv1alpha1.LeaderElectionConfiguration{LeaderElect: pointer.Bool(false)}
+			},
+			errors: []string{"leaderElection"},
+		},
+		{
+			name: "bind addresses",
+			mutate: func(c *ServiceConfiguration) {
+				c.Health.HealthProbeBindAddress = ":9081"
+				c.Metrics.BindAddress = ":9080"
+			},
+			errors: []string{"health", "metrics"},
+		},
+		{
+			name:   "sync period",
+			mutate: func(c *ServiceConfiguration) { c.SyncPeriod = &metav1.Duration{Duration: time.Hour} },
+			errors: []string{"syncPeriod"},
+		},
+		{
+			name:   "status namespace",
+			mutate: func(c *ServiceConfiguration) { c.Service.StatusNamespace = "other" },
+			errors: []string{"service.statusNamespace"},
+		},
+		{
+			name:   "operator image",
+			mutate: func(c *ServiceConfiguration) { c.Service.OperatorImage = "camel-kcp:latest" },
+			errors: []string{"service.operatorImage"},
+		},
+		{
+			name: "initializer",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.Initializer = &Initializer{WorkspaceTypes: []WorkspaceTypeReference{{Path: "root", Name: "camel-k"}}}
+			},
+			errors: []string{"service.initializer"},
+		},
+		{
+			name:   "max logical clusters",
+			mutate: func(c *ServiceConfiguration) { c.Service.Metrics.MaxLogicalClusters = pointer.Int32(10) },
+			errors: []string{"service.metrics.maxLogicalClusters"},
+		},
+		{
+			name: "default max logical clusters",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.Metrics.MaxLogicalClusters = pointer.Int32(DefaultMaxLogicalClusters)
+			},
+		},
+		{
+			name:   "APIExport name",
+			mutate: func(c *ServiceConfiguration) { c.Service.APIExports.CamelK.APIExportName = "other" },
+			// The APIExportEndpointSlice name defaults to the APIExport name
+			errors: []string{
+				"service.apiExports.camel-k.apiExportName",
+				"service.apiExports.camel-k.apiExportEndpointSliceName",
+			},
+		},
+		{
+			name:   "APIExportEndpointSlice name",
+			mutate: func(c *ServiceConfiguration) { c.Service.APIExports.Kaoto.APIExportEndpointSliceName = "other" },
+			errors: []string{"service.apiExports.kaoto.apiExportEndpointSliceName"},
+		},
+		{
+			name: "Kaoto ingress profile",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.APIExports.Kaoto.OnAPIBinding.Kaoto.Ingress.Profile = IngressProfileOpenShiftRoute
+			},
+			errors: []string{"service.apiExports.kaoto.onApiBinding.kaoto.ingress.profile"},
+		},
+		{
+			name: "add-on APIExports",
+			mutate: func(c *ServiceConfiguration) {
+				c.Service.APIExports.AddOns = append(c.Service.APIExports.AddOns, AddOnAPIExport{
+					LocalAPIExportReference: LocalAPIExportReference{APIExportName: "strimzi"},
+				})
+			},
+			errors: []string{"service.apiExports.addOns"},
+		},
+		{
+			name:   "add-on APIExport name",
+			mutate: func(c *ServiceConfiguration) { c.Service.APIExports.AddOns[0].APIExportName = "other" },
+			errors: []string{
+				"service.apiExports.addOns[0].apiExportName",
+				"service.apiExports.addOns[0].apiExportEndpointSliceName",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cfg := validServiceConfiguration()
+			test.mutate(cfg)
+
+			g.Expect(fieldPaths(cfg.ValidateUpdate(validServiceConfiguration()))).To(ConsistOf(test.errors))
+		})
+	}
+}
+
+func fieldPaths(errs field.ErrorList) []string {
+	paths := make([]string, 0, len(errs))
+	for _, err := range errs {
+		paths = append(paths, err.Field)
+	}
+	return paths
+}
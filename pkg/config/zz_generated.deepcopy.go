@@ -23,6 +23,7 @@ limitations under the License.
 package config
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -31,6 +32,13 @@ func (in *APIExports) DeepCopyInto(out *APIExports) {
 	*out = *in
 	in.CamelK.DeepCopyInto(&out.CamelK)
 	in.Kaoto.DeepCopyInto(&out.Kaoto)
+	if in.AddOns != nil {
+		in, out := &in.AddOns, &out.AddOns
+		*out = make([]AddOnAPIExport, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIExports.
@@ -43,11 +51,30 @@ func (in *APIExports) DeepCopy() *APIExports {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddOnAPIExport) DeepCopyInto(out *AddOnAPIExport) {
+	*out = *in
+	out.LocalAPIExportReference = in.LocalAPIExportReference
+	in.OnAPIBinding.DeepCopyInto(&out.OnAPIBinding)
+	in.OnAPIUnbinding.DeepCopyInto(&out.OnAPIUnbinding)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddOnAPIExport.
+func (in *AddOnAPIExport) DeepCopy() *AddOnAPIExport {
+	if in == nil {
+		return nil
+	}
+	out := new(AddOnAPIExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CamelKAPIExport) DeepCopyInto(out *CamelKAPIExport) {
 	*out = *in
 	out.LocalAPIExportReference = in.LocalAPIExportReference
 	in.OnAPIBinding.DeepCopyInto(&out.OnAPIBinding)
+	in.OnAPIUnbinding.DeepCopyInto(&out.OnAPIUnbinding)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CamelKAPIExport.
@@ -60,6 +87,76 @@ func (in *CamelKAPIExport) DeepCopy() *CamelKAPIExport {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CamelKOverride) DeepCopyInto(out *CamelKOverride) {
+	*out = *in
+	if in.LogicalClusters != nil {
+		in, out := &in.LogicalClusters, &out.LogicalClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkspacePaths != nil {
+		in, out := &in.WorkspacePaths, &out.WorkspacePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CamelKOverride.
+func (in *CamelKOverride) DeepCopy() *CamelKOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(CamelKOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Initializer) DeepCopyInto(out *Initializer) {
+	*out = *in
+	if in.WorkspaceTypes != nil {
+		in, out := &in.WorkspaceTypes, &out.WorkspaceTypes
+		*out = make([]WorkspaceTypeReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Initializer.
+func (in *Initializer) DeepCopy() *Initializer {
+	if in == nil {
+		return nil
+	}
+	out := new(Initializer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IntegrationPlatform) DeepCopyInto(out *IntegrationPlatform) {
 	*out = *in
@@ -82,6 +179,7 @@ func (in *KaotoAPIExport) DeepCopyInto(out *KaotoAPIExport) {
 	*out = *in
 	out.LocalAPIExportReference = in.LocalAPIExportReference
 	in.OnAPIBinding.DeepCopyInto(&out.OnAPIBinding)
+	in.OnAPIUnbinding.DeepCopyInto(&out.OnAPIUnbinding)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KaotoAPIExport.
@@ -94,6 +192,116 @@ func (in *KaotoAPIExport) DeepCopy() *KaotoAPIExport {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KaotoComponent) DeepCopyInto(out *KaotoComponent) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KaotoComponent.
+func (in *KaotoComponent) DeepCopy() *KaotoComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(KaotoComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KaotoIngress) DeepCopyInto(out *KaotoIngress) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(KaotoIngressTLS)
+		**out = **in
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KaotoIngress.
+func (in *KaotoIngress) DeepCopy() *KaotoIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(KaotoIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KaotoIngressTLS) DeepCopyInto(out *KaotoIngressTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KaotoIngressTLS.
+func (in *KaotoIngressTLS) DeepCopy() *KaotoIngressTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(KaotoIngressTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KaotoSpec) DeepCopyInto(out *KaotoSpec) {
+	*out = *in
+	in.UI.DeepCopyInto(&out.UI)
+	in.Backend.DeepCopyInto(&out.Backend)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.Ingress.DeepCopyInto(&out.Ingress)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KaotoSpec.
+func (in *KaotoSpec) DeepCopy() *KaotoSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KaotoSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalAPIExportReference) DeepCopyInto(out *LocalAPIExportReference) {
 	*out = *in
@@ -109,6 +317,75 @@ func (in *LocalAPIExportReference) DeepCopy() *LocalAPIExportReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metrics) DeepCopyInto(out *Metrics) {
+	*out = *in
+	if in.MaxLogicalClusters != nil {
+		in, out := &in.MaxLogicalClusters, &out.MaxLogicalClusters
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metrics.
+func (in *Metrics) DeepCopy() *Metrics {
+	if in == nil {
+		return nil
+	}
+	out := new(Metrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnAPIUnbinding) DeepCopyInto(out *OnAPIUnbinding) {
+	*out = *in
+	if in.CleanupPolicies != nil {
+		in, out := &in.CleanupPolicies, &out.CleanupPolicies
+		*out = make(map[string]CleanupPolicy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnAPIUnbinding.
+func (in *OnAPIUnbinding) DeepCopy() *OnAPIUnbinding {
+	if in == nil {
+		return nil
+	}
+	out := new(OnAPIUnbinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OnAddOnAPIBinding) DeepCopyInto(out *OnAddOnAPIBinding) {
+	*out = *in
+	if in.DefaultPlacement != nil {
+		in, out := &in.DefaultPlacement, &out.DefaultPlacement
+		*out = new(Placement)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnAddOnAPIBinding.
+func (in *OnAddOnAPIBinding) DeepCopy() *OnAddOnAPIBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(OnAddOnAPIBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OnCamelKAPIBinding) DeepCopyInto(out *OnCamelKAPIBinding) {
 	*out = *in
@@ -122,6 +399,25 @@ func (in *OnCamelKAPIBinding) DeepCopyInto(out *OnCamelKAPIBinding) {
 		*out = new(Placement)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]CamelKOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProvisionRegistry != nil {
+		in, out := &in.ProvisionRegistry, &out.ProvisionRegistry
+		*out = new(RegistryProvisioning)
+		**out = **in
+	}
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnCamelKAPIBinding.
@@ -142,6 +438,14 @@ func (in *OnKaotoAPIBinding) DeepCopyInto(out *OnKaotoAPIBinding) {
 		*out = new(Placement)
 		(*in).DeepCopyInto(*out)
 	}
+	in.Kaoto.DeepCopyInto(&out.Kaoto)
+	if in.Manifests != nil {
+		in, out := &in.Manifests, &out.Manifests
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OnKaotoAPIBinding.
@@ -171,6 +475,37 @@ func (in *Placement) DeepCopy() *Placement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryProvisioning) DeepCopyInto(out *RegistryProvisioning) {
+	*out = *in
+	out.CredentialsSecret = in.CredentialsSecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryProvisioning.
+func (in *RegistryProvisioning) DeepCopy() *RegistryProvisioning {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryProvisioning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceConfiguration) DeepCopyInto(out *ServiceConfiguration) {
 	*out = *in
@@ -202,6 +537,12 @@ func (in *ServiceConfiguration) DeepCopyObject() runtime.Object {
 func (in *ServiceConfigurationSpec) DeepCopyInto(out *ServiceConfigurationSpec) {
 	*out = *in
 	in.APIExports.DeepCopyInto(&out.APIExports)
+	if in.Initializer != nil {
+		in, out := &in.Initializer, &out.Initializer
+		*out = new(Initializer)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Metrics.DeepCopyInto(&out.Metrics)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceConfigurationSpec.
@@ -213,3 +554,18 @@ func (in *ServiceConfigurationSpec) DeepCopy() *ServiceConfigurationSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceTypeReference) DeepCopyInto(out *WorkspaceTypeReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceTypeReference.
+func (in *WorkspaceTypeReference) DeepCopy() *WorkspaceTypeReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceTypeReference)
+	in.DeepCopyInto(out)
+	return out
+}
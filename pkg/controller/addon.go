@@ -0,0 +1,210 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+
+	"github.com/apache/camel-k/pkg/util/log"
+	"github.com/apache/camel-k/pkg/util/monitoring"
+
+	"github.com/apache/camel-kcp/pkg/client"
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+// addOnFinalizer returns the finalizer of the APIBindings of the add-on APIExport with the given name.
+func addOnFinalizer(apiExportName string) string {
+	return "camel-kcp.apache.org/" + apiExportName
+}
+
+// AddAddOnController adds the controller that applies the manifests of the add-on APIExport with the given name,
+// into the consumer workspaces it's bound into.
+func AddAddOnController(mgr manager.Manager, c client.Client, cfg *config.Holder, status *StatusReporter, paths *WorkspacePaths, inventory *ManifestInventory, apiExportName string) error {
+	name := apiExportName + "-apibinding-controller"
+	finalizer := addOnFinalizer(apiExportName)
+
+	return builder.ControllerManagedBy(mgr).
+		Named(name).
+		For(&apisv1alpha1.APIBinding{}, builder.WithPredicates(
+			predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					binding, ok := e.ObjectNew.(*apisv1alpha1.APIBinding)
+					if !ok {
+						return false
+					}
+					if isDeleted(binding) {
+						return controllerutil.ContainsFinalizer(binding, finalizer)
+					}
+					return binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound
+				},
+				DeleteFunc: func(deleteEvent event.DeleteEvent) bool {
+					return false
+				},
+			})).
+		Watches(&configurationReloaded{cfg: cfg, client: mgr.GetClient()}, &handler.EnqueueRequestForObject{}).
+		Complete(monitoring.NewInstrumentedReconciler(
+			newInstrumentedReconciler(name, &addOnReconciler{
+				reconciler: reconciler{
+					cfg:       cfg,
+					client:    c,
+					recorder:  mgr.GetEventRecorderFor(name),
+					status:    status,
+					paths:     paths,
+					inventory: inventory,
+				},
+				apiExportName: apiExportName,
+				finalizer:     finalizer,
+			}),
+			schema.GroupVersionKind{
+				Group:   apisv1alpha1.SchemeGroupVersion.Group,
+				Version: apisv1alpha1.SchemeGroupVersion.Version,
+				Kind:    "APIBinding",
+			},
+		))
+}
+
+// addOnReconciler applies the manifests of an add-on APIExport, rather than provisioning dedicated resources.
+type addOnReconciler struct {
+	reconciler
+	apiExportName string
+	finalizer     string
+}
+
+func (r *addOnReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	rlog := Log.WithValues("api-binding", r.apiExportName, "request-name", request.Name)
+	rlog.Info("Reconciling APIBinding")
+
+	// Add the logical cluster to the context
+	ctx = kontext.WithCluster(ctx, logicalcluster.Name(request.ClusterName))
+
+	// Use the same configuration for the whole reconciliation
+	svcCfg := r.cfg.Get()
+	apiExport := svcCfg.Service.APIExports.AddOn(r.apiExportName)
+	if apiExport == nil {
+		// The add-on APIExports cannot be removed without restarting the service
+		return reconcile.Result{}, fmt.Errorf("add-on APIExport %s not found in the service configuration", r.apiExportName)
+	}
+
+	binding := &apisv1alpha1.APIBinding{}
+	if err := r.client.Get(ctx, request.NamespacedName, binding); err != nil {
+		return reconcile.Result{}, ctrl.IgnoreNotFound(err)
+	}
+
+	if isDeleted(binding) {
+		rlog.Info("Cleaning up APIBinding")
+		return reconcile.Result{}, r.cleanup(ctx, apiExport, binding, svcCfg.Service.IngressHost)
+	}
+
+	if err := r.addFinalizer(ctx, binding, r.finalizer); err != nil {
+		return reconcile.Result{}, err
+	}
+	workspaces.add(apiExport.APIExportName, logicalcluster.From(binding), maxLogicalClusters(r.cfg))
+
+	conditions := conditionsOf(binding)
+	result, err := r.provision(ctx, rlog, apiExport, binding, svcCfg.Service.IngressHost, &conditions)
+	if statusErr := r.reportStatus(ctx, binding, apiExport.APIExportName, conditions); statusErr != nil {
+		rlog.Error(statusErr, "Error reporting APIBinding status")
+		if err == nil {
+			return reconcile.Result{}, statusErr
+		}
+	}
+
+	return result, err
+}
+
+// provision creates the resources in the consumer workspace, and sets the conditions accordingly.
+func (r *addOnReconciler) provision(ctx context.Context, rlog log.Logger, apiExport *config.AddOnAPIExport, binding *apisv1alpha1.APIBinding, ingressHost string, conditions *[]metav1.Condition) (reconcile.Result, error) {
+	path, err := r.paths.get(ctx, logicalcluster.From(binding))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// The provisioning carries on when the placement is not ready yet, and the request is requeued at the end
+	var notReadyErr error
+
+	if placement := apiExport.OnAPIBinding.DefaultPlacement; placement != nil {
+		err := r.applyPlacement(ctx, placement)
+		setCondition(conditions, binding, PlacementReady, err)
+		if isNotReady(err) {
+			notReadyErr = err
+		} else if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	err = r.applyManifests(ctx, binding, apiExport.APIExportName, &apiExport.OnAPIUnbinding, apiExport.OnAPIBinding.Manifests, manifestValues(binding, path, ingressHost))
+	setCondition(conditions, binding, AddOnReady, err)
+	if err != nil {
+		return requeueIfNotFound(rlog, err)
+	}
+
+	return requeueIfNotFound(rlog, notReadyErr)
+}
+
+// cleanup deletes the resources created in the consumer workspace, in the reverse order of the manifests,
+// according to their cleanup policy, and removes the finalizer from the APIBinding.
+func (r *addOnReconciler) cleanup(ctx context.Context, apiExport *config.AddOnAPIExport, binding *apisv1alpha1.APIBinding, ingressHost string) error {
+	onUnbinding := &apiExport.OnAPIUnbinding
+
+	path, err := r.paths.get(ctx, logicalcluster.From(binding))
+	if err != nil {
+		return err
+	}
+
+	err = r.deleteManifests(ctx, binding, apiExport.APIExportName, onUnbinding, apiExport.OnAPIBinding.Manifests, manifestValues(binding, path, ingressHost))
+	if err != nil {
+		return err
+	}
+
+	if placement := apiExport.OnAPIBinding.DefaultPlacement; placement != nil {
+		err := r.maybeDelete(ctx, onUnbinding, "Placement", placement.Name,
+			r.client.KcpSchedulingV1alpha1().Placements().Delete)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := r.status.remove(ctx, apiExport.APIExportName, logicalcluster.From(binding)); err != nil {
+		return err
+	}
+
+	if err := r.removeFinalizer(ctx, binding, r.finalizer); err != nil {
+		return err
+	}
+	workspaces.remove(apiExport.APIExportName, logicalcluster.From(binding))
+
+	return nil
+}
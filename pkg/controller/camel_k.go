@@ -19,13 +19,18 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/kontext"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -35,7 +40,10 @@ import (
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
 	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/log"
 	"github.com/apache/camel-k/pkg/util/monitoring"
 
 	"github.com/apache/camel-kcp/pkg/client"
@@ -43,7 +51,9 @@ import (
 	"github.com/apache/camel-kcp/pkg/platform"
 )
 
-func AddCamelKController(mgr manager.Manager, c client.Client, cfg *config.ServiceConfiguration) error {
+const camelKFinalizer = "camel-kcp.apache.org/camel-k"
+
+func AddCamelKController(mgr manager.Manager, c client.Client, cfg *config.Holder, status *StatusReporter, paths *WorkspacePaths, inventory *ManifestInventory, registry RegistryProvisioner) error {
 	return builder.ControllerManagedBy(mgr).
 		Named("camel-k-apibinding-controller").
 		For(&apisv1alpha1.APIBinding{}, builder.WithPredicates(
@@ -54,8 +64,8 @@ func AddCamelKController(mgr manager.Manager, c client.Client, cfg *config.Servi
 					if !ok {
 						return false
 					}
-					if binding.DeletionTimestamp != nil && !binding.DeletionTimestamp.IsZero() {
-						return false
+					if isDeleted(binding) {
+						return controllerutil.ContainsFinalizer(binding, camelKFinalizer)
 					}
 					return binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound
 				},
@@ -63,14 +73,19 @@ func AddCamelKController(mgr manager.Manager, c client.Client, cfg *config.Servi
 					return false
 				},
 			})).
+		Watches(&configurationReloaded{cfg: cfg, client: mgr.GetClient()}, &handler.EnqueueRequestForObject{}).
 		Complete(monitoring.NewInstrumentedReconciler(
-			&camelKReconciler{
+			newInstrumentedReconciler("camel-k-apibinding-controller", &camelKReconciler{
 				reconciler: reconciler{
-					cfg:      cfg,
-					client:   c,
-					recorder: mgr.GetEventRecorderFor("camel-k-apibinding-controller"),
+					cfg:       cfg,
+					client:    c,
+					recorder:  mgr.GetEventRecorderFor("camel-k-apibinding-controller"),
+					status:    status,
+					paths:     paths,
+					inventory: inventory,
 				},
-			},
+				registry: registry,
+			}),
 			schema.GroupVersionKind{
 				Group:   apisv1alpha1.SchemeGroupVersion.Group,
 				Version: apisv1alpha1.SchemeGroupVersion.Version,
@@ -81,6 +96,7 @@ func AddCamelKController(mgr manager.Manager, c client.Client, cfg *config.Servi
 
 type camelKReconciler struct {
 	reconciler
+	registry RegistryProvisioner
 }
 
 func (r *camelKReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
@@ -90,52 +106,261 @@ func (r *camelKReconciler) Reconcile(ctx context.Context, request reconcile.Requ
 	// Add the logical cluster to the context
 	ctx = kontext.WithCluster(ctx, logicalcluster.Name(request.ClusterName))
 
-	if ip := r.cfg.Service.APIExports.CamelK.OnAPIBinding.DefaultPlatform; ip != nil {
-		if ip.Namespace == "" {
-			ip.Namespace = platform.GetOperatorNamespace()
+	// Use the same configuration for the whole reconciliation
+	svcCfg := r.cfg.Get()
+	apiExport := &svcCfg.Service.APIExports.CamelK
+
+	binding := &apisv1alpha1.APIBinding{}
+	if err := r.client.Get(ctx, request.NamespacedName, binding); err != nil {
+		return reconcile.Result{}, ctrl.IgnoreNotFound(err)
+	}
+
+	if isDeleted(binding) {
+		rlog.Info("Cleaning up APIBinding")
+		return reconcile.Result{}, r.cleanup(ctx, apiExport, binding, svcCfg.Service.IngressHost)
+	}
+
+	if err := r.addFinalizer(ctx, binding, camelKFinalizer); err != nil {
+		return reconcile.Result{}, err
+	}
+	workspaces.add(apiExport.APIExportName, logicalcluster.From(binding), maxLogicalClusters(r.cfg))
+
+	conditions := conditionsOf(binding)
+	result, err := r.provision(ctx, rlog, apiExport, binding, svcCfg.Service.IngressHost, &conditions)
+	if statusErr := r.reportStatus(ctx, binding, apiExport.APIExportName, conditions); statusErr != nil {
+		rlog.Error(statusErr, "Error reporting APIBinding status")
+		if err == nil {
+			return reconcile.Result{}, statusErr
 		}
-		if ip.Name == "" {
-			ip.Name = platform.DefaultPlatformName
+	}
+
+	return result, err
+}
+
+// provision creates the resources in the consumer workspace, and sets the conditions accordingly.
+func (r *camelKReconciler) provision(ctx context.Context, rlog log.Logger, apiExport *config.CamelKAPIExport, binding *apisv1alpha1.APIBinding, ingressHost string, conditions *[]metav1.Condition) (reconcile.Result, error) {
+	path, err := r.paths.get(ctx, logicalcluster.From(binding))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	onBinding, err := apiExport.OnAPIBinding.ForWorkspace(logicalcluster.From(binding).String(), path.String())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	namespace := operatorNamespace(onBinding)
+
+	// The provisioning carries on when a resource is not ready yet, and the request is requeued at the end
+	var notReadyErr error
+
+	if ip := defaultPlatform(onBinding, namespace); ip != nil {
+		wasReady := meta.IsStatusConditionTrue(*conditions, PlatformReady)
+		err := r.maybeCreateNamespace(ctx, ip.Namespace, apiExport.APIExportName)
+		if err == nil && ip.Spec.Profile == "" {
+			ip.Spec.Profile, err = r.platformProfile(ctx)
 		}
+		if err == nil && onBinding.ProvisionRegistry != nil {
+			err = r.provisionRegistry(ctx, onBinding.ProvisionRegistry, ip, logicalcluster.From(binding))
+		}
+		if err == nil {
+			err = r.applyPlatform(ctx, ip)
+		}
+		if err == nil {
+			err = r.platformReady(ctx, ip)
+		}
+		if err == nil && !wasReady {
+			observePlatformReady(binding)
+		}
+		setCondition(conditions, binding, PlatformReady, err)
+		if isNotReady(err) {
+			notReadyErr = err
+		} else if err != nil {
+			return requeueIfNotFound(rlog, err)
+		}
+	}
 
-		if err := r.maybeCreateNamespace(ctx, ip.Namespace); err != nil {
-			if errors.IsNotFound(err) {
-				rlog.Debug("Bound APIs are not yet found")
-				return reconcile.Result{Requeue: true}, nil
-			}
+	if placement := onBinding.DefaultPlacement; placement != nil {
+		err := r.applyPlacement(ctx, placement)
+		setCondition(conditions, binding, PlacementReady, err)
+		if isNotReady(err) {
+			notReadyErr = err
+		} else if err != nil {
 			return reconcile.Result{}, err
 		}
+	}
 
-		if err := r.maybeCreatePlatform(ctx, ip); err != nil {
-			if errors.IsNotFound(err) {
-				rlog.Debug("Bound APIs are not yet found")
-				return reconcile.Result{Requeue: true}, nil
-			}
-			return reconcile.Result{}, err
+	// The resources of the manifests that are removed from the configuration are pruned
+	if manifests := onBinding.Manifests; len(manifests) > 0 || meta.FindStatusCondition(*conditions, ManifestsReady) != nil {
+		err := r.applyManifests(ctx, binding, apiExport.APIExportName, &apiExport.OnAPIUnbinding, manifests, manifestValues(binding, path, ingressHost))
+		setCondition(conditions, binding, ManifestsReady, err)
+		if err != nil {
+			return requeueIfNotFound(rlog, err)
+		}
+		if len(manifests) == 0 {
+			meta.RemoveStatusCondition(conditions, ManifestsReady)
 		}
 	}
 
-	if placement := r.cfg.Service.APIExports.CamelK.OnAPIBinding.DefaultPlacement; placement != nil {
-		if err := r.maybeCreatePlacement(ctx, placement); err != nil {
-			return reconcile.Result{}, err
+	return requeueIfNotFound(rlog, notReadyErr)
+}
+
+// operatorNamespace returns the operator namespace of the consumer workspace, from the default integration platform
+// namespace, defaulting to camel-k. It's only resolved from the configuration, as the namespace is created, and deleted,
+// in the consumer workspace, so that the namespaces claimed by the Camel K APIExport must include the configured ones.
+func operatorNamespace(onBinding *config.OnCamelKAPIBinding) string {
+	if ip := onBinding.DefaultPlatform; ip != nil && ip.Namespace != "" {
+		return ip.Namespace
+	}
+	return platform.DefaultNamespaceName
+}
+
+// CamelKOperatorNamespace returns the function resolving the operator namespace of the logical cluster of the context,
+// as the Camel K APIBinding reconciler does.
+func CamelKOperatorNamespace(cfg *config.Holder, paths *WorkspacePaths) client.OperatorNamespaceFunc {
+	return func(ctx context.Context) (string, error) {
+		cluster, ok := kontext.ClusterFrom(ctx)
+		if !ok {
+			return "", fmt.Errorf("no logical cluster in context")
+		}
+		path, err := paths.get(ctx, cluster)
+		if err != nil {
+			return "", err
+		}
+		onBinding, err := cfg.Get().Service.APIExports.CamelK.OnAPIBinding.ForWorkspace(cluster.String(), path.String())
+		if err != nil {
+			return "", err
 		}
+		return operatorNamespace(onBinding), nil
 	}
+}
 
-	return reconcile.Result{}, nil
+// defaultPlatform returns a copy of the configured default integration platform,
+// in the operator namespace, with its name defaulted, or nil if none is configured.
+func defaultPlatform(onBinding *config.OnCamelKAPIBinding, namespace string) *config.IntegrationPlatform {
+	platformConfig := onBinding.DefaultPlatform
+	if platformConfig == nil {
+		return nil
+	}
+	ip := platformConfig.DeepCopy()
+	ip.Namespace = namespace
+	if ip.Name == "" {
+		ip.Name = platform.DefaultPlatformName
+	}
+	return ip
 }
 
-func (r *camelKReconciler) maybeCreatePlatform(ctx context.Context, platformConfig *config.IntegrationPlatform) error {
+// applyPlatform server-side applies the configured integration platform, so that changes to the configuration
+// are rolled out to existing workspaces, while the fields that are not configured are left to the users.
+func (r *camelKReconciler) applyPlatform(ctx context.Context, platformConfig *config.IntegrationPlatform) error {
 	ip := &camelv1.IntegrationPlatform{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: camelv1.SchemeGroupVersion.String(),
+			Kind:       camelv1.IntegrationPlatformKind,
+		},
 		ObjectMeta: platformConfig.ObjectMeta,
 		Spec:       platformConfig.Spec,
 	}
 
-	// Use the controller-runtime caching client
-	if err := r.client.Get(ctx, ctrl.ObjectKeyFromObject(ip), ip); errors.IsNotFound(err) {
-		return r.client.Create(ctx, ip)
-	} else if err != nil {
+	// Use the controller-runtime client
+	return r.apply(ctx, ip)
+}
+
+// platformProfile returns the Knative profile if Knative Serving is bound in the consumer workspace,
+// or the Kubernetes profile otherwise. Camel K cannot determine it, as it discovers the APIs bound
+// in all the consumer workspaces.
+func (r *camelKReconciler) platformProfile(ctx context.Context) (camelv1.TraitProfile, error) {
+	installed, err := client.IsAPIResourceInstalled(ctx, r.client, servingv1.SchemeGroupVersion.String(), "Service")
+	if err != nil {
+		return "", fmt.Errorf("error discovering Knative Serving API: %w", err)
+	}
+	if installed {
+		return camelv1.TraitProfileKnative, nil
+	}
+	return camelv1.TraitProfileKubernetes, nil
+}
+
+// platformReady returns a not ready error, until the integration platform has been initialized by the operator.
+func (r *camelKReconciler) platformReady(ctx context.Context, platformConfig *config.IntegrationPlatform) error {
+	ip, err := r.client.CamelV1().IntegrationPlatforms(platformConfig.Namespace).Get(ctx, platformConfig.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	switch ip.Status.Phase {
+	case camelv1.IntegrationPlatformPhaseReady:
+		return nil
+	case camelv1.IntegrationPlatformPhaseError:
+		return fmt.Errorf("IntegrationPlatform %s/%s is in error phase", ip.Namespace, ip.Name)
+	default:
+		return notReady("IntegrationPlatform %s/%s is not ready yet", ip.Namespace, ip.Name)
+	}
+}
+
+// cleanup deletes the resources created in the consumer workspace, according to their cleanup policy,
+// and removes the finalizer from the APIBinding.
+func (r *camelKReconciler) cleanup(ctx context.Context, apiExport *config.CamelKAPIExport, binding *apisv1alpha1.APIBinding, ingressHost string) error {
+	onUnbinding := &apiExport.OnAPIUnbinding
+
+	path, err := r.paths.get(ctx, logicalcluster.From(binding))
+	if err != nil {
+		return err
+	}
+	onBinding, err := apiExport.OnAPIBinding.ForWorkspace(logicalcluster.From(binding).String(), path.String())
+	if err != nil {
+		return err
+	}
+	namespace := operatorNamespace(onBinding)
+
+	// The resources of the manifests are deleted first, as they may depend on the other ones
+	err = r.deleteManifests(ctx, binding, apiExport.APIExportName, onUnbinding, onBinding.Manifests, manifestValues(binding, path, ingressHost))
+	if err != nil {
+		return err
+	}
+
+	if placement := onBinding.DefaultPlacement; placement != nil {
+		err := r.maybeDelete(ctx, onUnbinding, "Placement", placement.Name,
+			r.client.KcpSchedulingV1alpha1().Placements().Delete)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ip := defaultPlatform(onBinding, namespace); ip != nil {
+		err := r.maybeDelete(ctx, onUnbinding, camelv1.IntegrationPlatformKind, ip.Name,
+			r.client.CamelV1().IntegrationPlatforms(ip.Namespace).Delete)
+		if err != nil {
+			return err
+		}
+
+		if registry := onBinding.ProvisionRegistry; registry != nil {
+			err = r.maybeDelete(ctx, onUnbinding, "Secret", registry.SecretName,
+				func(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+					err := r.client.CoreV1().Secrets(ip.Namespace).Delete(ctx, name, opts)
+					if err != nil && !errors.IsNotFound(err) {
+						return err
+					}
+					return r.registry.Deprovision(ctx, registry, logicalcluster.From(binding))
+				})
+			if err != nil {
+				return err
+			}
+		}
+
+		// The namespace is only deleted if it has been created for the APIExport
+		err = r.maybeDelete(ctx, onUnbinding, "Namespace", ip.Namespace,
+			r.deleteCreatedNamespace(apiExport.APIExportName))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := r.status.remove(ctx, apiExport.APIExportName, logicalcluster.From(binding)); err != nil {
+		return err
+	}
+
+	if err := r.removeFinalizer(ctx, binding, camelKFinalizer); err != nil {
 		return err
 	}
+	workspaces.remove(apiExport.APIExportName, logicalcluster.From(binding))
 
 	return nil
 }
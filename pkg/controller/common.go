@@ -19,14 +19,23 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	schedulingv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/scheduling/v1alpha1"
 
 	"github.com/apache/camel-k/pkg/util/log"
@@ -37,16 +46,26 @@ import (
 
 const applyManager = "camel-kcp"
 
+// notReadyRequeueDelay is the delay after which the provisioned resources that are not yet ready are checked again.
+const notReadyRequeueDelay = 5 * time.Second
+
 var Log = log.Log.WithName("controller").WithName("api-binding")
 
 type reconciler struct {
 	reconcile.Reconciler
-	cfg      *config.ServiceConfiguration
-	client   client.Client
-	recorder record.EventRecorder
+	cfg       *config.Holder
+	client    client.Client
+	recorder  record.EventRecorder
+	status    *StatusReporter
+	paths     *WorkspacePaths
+	inventory *ManifestInventory
 }
 
-func (r *reconciler) maybeCreateNamespace(ctx context.Context, name string) error {
+// CreatedByLabel is the label of the namespaces created in the consumer workspaces, set to the name of the APIExport
+// they are created for, so that only the namespaces that did not exist before are deleted when it is unbound.
+const CreatedByLabel = "camel-kcp.apache.org/created-by"
+
+func (r *reconciler) maybeCreateNamespace(ctx context.Context, name string, apiExportName string) error {
 	_, err := r.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
 	if err == nil {
 		return nil
@@ -61,6 +80,9 @@ func (r *reconciler) maybeCreateNamespace(ctx context.Context, name string) erro
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
+			Labels: map[string]string{
+				CreatedByLabel: apiExportName,
+			},
 		},
 	}
 
@@ -69,22 +91,273 @@ func (r *reconciler) maybeCreateNamespace(ctx context.Context, name string) erro
 	return err
 }
 
-// +kubebuilder:rbac:groups="scheduling.kcp.io",resources=placements,verbs=get;create
+// deleteCreatedNamespace returns the function that deletes the namespace, only if it has been created for the APIExport.
+func (r *reconciler) deleteCreatedNamespace(apiExportName string) deleteFunc {
+	return func(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+		namespace, err := r.client.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if namespace.Labels[CreatedByLabel] != apiExportName {
+			return nil
+		}
+		opts.Preconditions = metav1.NewUIDPreconditions(string(namespace.UID))
+		return r.client.CoreV1().Namespaces().Delete(ctx, name, opts)
+	}
+}
+
+// +kubebuilder:rbac:groups="scheduling.kcp.io",resources=placements,verbs=get;create;patch
 
-func (r *reconciler) maybeCreatePlacement(ctx context.Context, placementConfig *config.Placement) error {
+// applyPlacement server-side applies the configured placement, so that changes to the configuration are rolled out
+// to existing workspaces, and returns a not ready error until the placement has selected a location.
+func (r *reconciler) applyPlacement(ctx context.Context, placementConfig *config.Placement) error {
 	placement := &schedulingv1alpha1.Placement{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: schedulingv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Placement",
+		},
 		ObjectMeta: placementConfig.ObjectMeta,
 		Spec:       placementConfig.Spec,
 	}
 
+	// Use the controller-runtime client
+	if err := r.apply(ctx, placement); err != nil {
+		return err
+	}
+
 	// Use client-go non-caching client
-	if _, err := r.client.KcpSchedulingV1alpha1().Placements().Get(ctx, placement.Name, metav1.GetOptions{}); errors.IsNotFound(err) {
-		if _, err := r.client.KcpSchedulingV1alpha1().Placements().Create(ctx, placement, metav1.CreateOptions{}); err != nil {
-			return err
-		}
-	} else if err != nil {
+	existing, err := r.client.KcpSchedulingV1alpha1().Placements().Get(ctx, placement.Name, metav1.GetOptions{})
+	if err != nil {
 		return err
 	}
 
+	// The placement is pending until a location is selected
+	if phase := existing.Status.Phase; phase == "" || phase == schedulingv1alpha1.PlacementPending {
+		return notReady("Placement %s has not selected a location yet", placement.Name)
+	}
+
 	return nil
 }
+
+// apply server-side applies the desired state of the object, with the camel-kcp field manager.
+// The object must have its type meta set. Only the fields that are set are managed, and the fields
+// owned by other managers are left untouched.
+func (r *reconciler) apply(ctx context.Context, obj ctrl.Object) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	delete(u, "status")
+	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+
+	return r.client.Patch(ctx, &unstructured.Unstructured{Object: u}, ctrl.Apply, ctrl.FieldOwner(applyManager), ctrl.ForceOwnership)
+}
+
+// addFinalizer adds the finalizer to the APIBinding, so that the resources created in the consumer workspace
+// can be cleaned up when the APIExport is unbound.
+func (r *reconciler) addFinalizer(ctx context.Context, binding *apisv1alpha1.APIBinding, finalizer string) error {
+	if controllerutil.ContainsFinalizer(binding, finalizer) {
+		return nil
+	}
+	patch := ctrl.MergeFromWithOptions(binding.DeepCopy(), ctrl.MergeFromWithOptimisticLock{})
+	controllerutil.AddFinalizer(binding, finalizer)
+	if err := r.client.Patch(ctx, binding, patch); err != nil {
+		return err
+	}
+	// The APIBinding is reconciled for the first time since it's bound, so that the APIs it binds are discovered
+	r.client.InvalidateDiscovery()
+	return nil
+}
+
+// removeFinalizer removes the finalizer from the APIBinding, once the cleanup is completed.
+func (r *reconciler) removeFinalizer(ctx context.Context, binding *apisv1alpha1.APIBinding, finalizer string) error {
+	if !controllerutil.ContainsFinalizer(binding, finalizer) {
+		return nil
+	}
+	patch := ctrl.MergeFromWithOptions(binding.DeepCopy(), ctrl.MergeFromWithOptimisticLock{})
+	controllerutil.RemoveFinalizer(binding, finalizer)
+	if err := r.client.Patch(ctx, binding, patch); err != nil {
+		return err
+	}
+	// The APIBinding is unbound, so that the APIs it binds are no longer discovered
+	r.client.InvalidateDiscovery()
+	return nil
+}
+
+type deleteFunc func(ctx context.Context, name string, opts metav1.DeleteOptions) error
+
+// maybeDelete deletes the resource of the given kind, unless its cleanup policy is to retain it.
+func (r *reconciler) maybeDelete(ctx context.Context, onUnbinding *config.OnAPIUnbinding, kind string, name string, del deleteFunc) error {
+	if onUnbinding.CleanupPolicyFor(kind) == config.CleanupPolicyRetain {
+		return nil
+	}
+	propagationPolicy := metav1.DeletePropagationBackground
+	if err := del(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// manifestValues returns the values the manifest templates are executed with, for the consumer workspace
+// of the APIBinding. The workspace path is the one recorded by the initializer, if any, and defaults to
+// the logical cluster name.
+func manifestValues(binding *apisv1alpha1.APIBinding, path logicalcluster.Path, ingressHost string) *config.ManifestValues {
+	cluster := logicalcluster.From(binding)
+	if path.Empty() {
+		path = cluster.Path()
+	}
+	return &config.ManifestValues{
+		LogicalCluster: cluster.String(),
+		WorkspacePath:  path.String(),
+		IngressHost:    ingressHost,
+	}
+}
+
+// ManagedByLabel is the label of the resources applied from the manifests, set to the name of the APIExport
+// they are applied for, so that only the resources that are labelled are pruned, or deleted when it is unbound.
+const ManagedByLabel = "camel-kcp.apache.org/managed-by"
+
+// manifestObject is the reference to a resource applied from the manifests, in the inventory.
+type manifestObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func manifestObjectOf(obj *unstructured.Unstructured) manifestObject {
+	return manifestObject{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+func (o manifestObject) unstructured() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(o.APIVersion)
+	u.SetKind(o.Kind)
+	u.SetNamespace(o.Namespace)
+	u.SetName(o.Name)
+	return u
+}
+
+// applyManifests renders the manifest templates, and server-side applies the resources, in order, labelled with
+// the APIExport name. The applied resources are recorded in the inventory of the APIBinding, and the resources of
+// the inventory that are no longer rendered, e.g., when their manifests are removed from the configuration, are
+// deleted in the reverse order, unless their cleanup policy is to retain them.
+func (r *reconciler) applyManifests(ctx context.Context, binding *apisv1alpha1.APIBinding, apiExport string, onUnbinding *config.OnAPIUnbinding, manifests []runtime.RawExtension, values *config.ManifestValues) error {
+	cluster := logicalcluster.From(binding)
+	objects, err := config.RenderManifests(manifests, values)
+	if err != nil {
+		return err
+	}
+	applied, _, err := r.inventory.get(ctx, apiExport, cluster)
+	if err != nil {
+		return err
+	}
+
+	inventory := make([]manifestObject, 0, len(objects))
+	rendered := make(map[manifestObject]bool, len(objects))
+	for _, obj := range objects {
+		inventory = append(inventory, manifestObjectOf(obj))
+		rendered[manifestObjectOf(obj)] = true
+	}
+	var pruned []manifestObject
+	for _, obj := range applied {
+		if !rendered[obj] {
+			pruned = append(pruned, obj)
+		}
+	}
+
+	// The resources are recorded before they are applied, so that they are not leaked if the provisioning fails
+	if err := r.inventory.record(ctx, apiExport, cluster, append(append([]manifestObject{}, inventory...), pruned...)); err != nil {
+		return err
+	}
+
+	for _, obj := range objects {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[ManagedByLabel] = apiExport
+		obj.SetLabels(labels)
+		if err := r.client.Patch(ctx, obj, ctrl.Apply, ctrl.FieldOwner(applyManager), ctrl.ForceOwnership); err != nil {
+			return fmt.Errorf("error applying %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+
+	for i := len(pruned) - 1; i >= 0; i-- {
+		obj := pruned[i]
+		if err := r.maybeDelete(ctx, onUnbinding, obj.Kind, obj.Name, r.deleteManagedObject(obj.unstructured(), apiExport)); err != nil {
+			return fmt.Errorf("error pruning %s %s: %w", obj.Kind, obj.Name, err)
+		}
+	}
+
+	return r.inventory.record(ctx, apiExport, cluster, inventory)
+}
+
+// deleteManifests deletes the resources of the inventory of the APIBinding, in the reverse order,
+// unless their cleanup policy is to retain them, and removes the inventory. The resources of the
+// rendered manifest templates are deleted instead, for the APIBindings whose inventory is not recorded.
+func (r *reconciler) deleteManifests(ctx context.Context, binding *apisv1alpha1.APIBinding, apiExport string, onUnbinding *config.OnAPIUnbinding, manifests []runtime.RawExtension, values *config.ManifestValues) error {
+	cluster := logicalcluster.From(binding)
+	inventory, ok, err := r.inventory.get(ctx, apiExport, cluster)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		objects, err := config.RenderManifests(manifests, values)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objects {
+			inventory = append(inventory, manifestObjectOf(obj))
+		}
+	}
+
+	for i := len(inventory) - 1; i >= 0; i-- {
+		obj := inventory[i]
+		if err := r.maybeDelete(ctx, onUnbinding, obj.Kind, obj.Name, r.deleteManagedObject(obj.unstructured(), apiExport)); err != nil {
+			return err
+		}
+	}
+	return r.inventory.record(ctx, apiExport, cluster, nil)
+}
+
+// deleteManagedObject returns the function that deletes the object, whatever its kind, only if it's labelled
+// as managed for the APIExport, so that the resources that the tenants have replaced are retained.
+func (r *reconciler) deleteManagedObject(obj *unstructured.Unstructured, apiExport string) deleteFunc {
+	return func(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := r.client.Get(ctx, ctrl.ObjectKey{Namespace: obj.GetNamespace(), Name: name}, u); err != nil {
+			return err
+		}
+		if u.GetLabels()[ManagedByLabel] != apiExport {
+			return nil
+		}
+		opts.Preconditions = metav1.NewUIDPreconditions(string(u.GetUID()))
+		return r.client.Delete(ctx, u, &ctrl.DeleteOptions{Raw: &opts})
+	}
+}
+
+// requeueIfNotFound requeues the request if the error is caused by the bound APIs not being served yet,
+// or by a provisioned resource not being ready yet.
+func requeueIfNotFound(rlog log.Logger, err error) (reconcile.Result, error) {
+	if errors.IsNotFound(err) {
+		rlog.Debug("Bound APIs are not yet found")
+		return reconcile.Result{Requeue: true}, nil
+	}
+	if isNotReady(err) {
+		rlog.Debug("Provisioned resources are not yet ready", "reason", err.Error())
+		return reconcile.Result{RequeueAfter: notReadyRequeueDelay}, nil
+	}
+	return reconcile.Result{}, err
+}
+
+// isDeleted returns whether the APIBinding is being deleted, and must be cleaned up.
+func isDeleted(binding *apisv1alpha1.APIBinding) bool {
+	return binding.DeletionTimestamp != nil && !binding.DeletionTimestamp.IsZero()
+}
@@ -19,16 +19,18 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"net/url"
-	"reflect"
 
 	networkingv1 "k8s.io/api/networking/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/kontext"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -44,43 +46,52 @@ import (
 	"github.com/apache/camel-kcp/pkg/config"
 )
 
-func AddKaotoIngressController(mgr manager.Manager, c client.Client, cfg *config.ServiceConfiguration) error {
+const kaotoIngressAnnotation = "kaoto.io/ingress"
+
+// nginxIngressClassName is the name of the IngressClass of the NGINX Ingress controller,
+// that the rewrite annotations of the Nginx profile are specific to.
+const nginxIngressClassName = "nginx"
+
+var (
+	routeGVK = schema.GroupVersionKind{
+		Group:   "route.openshift.io",
+		Version: "v1",
+		Kind:    "Route",
+	}
+	httpRouteGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1beta1",
+		Kind:    "HTTPRoute",
+	}
+)
+
+func AddKaotoIngressController(mgr manager.Manager, c client.Client, cfg *config.Holder) error {
+	// The ingress profile cannot be changed without restarting
+	gvk := kaotoIngressGVK(&cfg.Get().Service.APIExports.Kaoto.OnAPIBinding.Kaoto)
+
 	return builder.ControllerManagedBy(mgr).
 		Named("kaoto-ingress-controller").
-		For(&networkingv1.Ingress{}, builder.WithPredicates(
+		For(KaotoIngressObject(&cfg.Get().Service.APIExports.Kaoto.OnAPIBinding.Kaoto), builder.WithPredicates(
+			predicate.NewPredicateFuncs(func(object ctrl.Object) bool {
+				return object.GetNamespace() == config.KaotoNamespace && object.GetName() == "kaoto"
+			}),
 			predicate.Funcs{
 				UpdateFunc: func(e event.UpdateEvent) bool {
-					previous, ok := e.ObjectOld.(*networkingv1.Ingress)
-					if !ok {
-						return false
-					}
-					ingress, ok := e.ObjectNew.(*networkingv1.Ingress)
-					if !ok {
-						return false
-					}
-					if ingress.Namespace != kaotoNamespaceName {
-						return false
-					}
-					if reflect.DeepEqual(previous.Status.LoadBalancer.Ingress, ingress.Status.LoadBalancer.Ingress) {
-						return false
-					}
-					return true
+					// Compare with the annotation, rather than the previous state, as the configuration may have changed
+					endpoint := kaotoEndpoint(&cfg.Get().Service.APIExports.Kaoto.OnAPIBinding.Kaoto, e.ObjectNew)
+					return endpoint != "" && endpoint != e.ObjectNew.GetAnnotations()[kaotoIngressAnnotation]
 				},
 			}),
 		).
 		Complete(monitoring.NewInstrumentedReconciler(
-			&kaotoIngressReconciler{
+			newInstrumentedReconciler("kaoto-ingress-controller", &kaotoIngressReconciler{
 				reconciler{
 					cfg:      cfg,
 					client:   c,
 					recorder: mgr.GetEventRecorderFor("kaoto-ingress-controller"),
 				},
-			},
-			schema.GroupVersionKind{
-				Group:   networkingv1.SchemeGroupVersion.Group,
-				Version: networkingv1.SchemeGroupVersion.Version,
-				Kind:    "Ingress",
-			},
+			}),
+			gvk,
 		))
 }
 
@@ -89,36 +100,287 @@ type kaotoIngressReconciler struct {
 }
 
 func (r *kaotoIngressReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	kaoto := &r.cfg.Get().Service.APIExports.Kaoto.OnAPIBinding.Kaoto
+
 	rlog := log.Log.WithName("controller").WithName("kaoto-ingress").WithValues("request-name", request.Name)
-	rlog.Info("Reconciling Ingress")
+	rlog.Info("Reconciling " + kaotoIngressGVK(kaoto).Kind)
 
 	// Add the logical cluster to the context
 	ctx = kontext.WithCluster(ctx, logicalcluster.Name(request.ClusterName))
 
-	ingress, err := r.client.NetworkingV1().Ingresses(request.Namespace).Get(ctx, request.Name, metav1.GetOptions{})
-	if err != nil && !errors.IsNotFound(err) {
-		return reconcile.Result{}, err
+	object := KaotoIngressObject(kaoto)
+	if err := r.client.Get(ctx, request.NamespacedName, object); err != nil {
+		return reconcile.Result{}, ctrl.IgnoreNotFound(err)
 	}
-	if len(ingress.Status.LoadBalancer.Ingress) == 0 {
+
+	endpoint := kaotoEndpoint(kaoto, object)
+	if endpoint == "" || object.GetAnnotations()[kaotoIngressAnnotation] == endpoint {
 		return reconcile.Result{}, nil
 	}
 
-	endpoint := url.URL{
-		Scheme: "http",
-		Host:   ingress.Status.LoadBalancer.Ingress[0].IP,
-		Path:   request.ClusterName + "/kaoto",
+	// The endpoint annotation is patched, rather than applied, as the resource is applied by the
+	// Kaoto APIBinding reconciler, with the same field manager
+	patch := ctrl.MergeFrom(object.DeepCopyObject().(ctrl.Object))
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
 	}
+	annotations[kaotoIngressAnnotation] = endpoint
+	object.SetAnnotations(annotations)
 
-	ingressConfig := networkingv1ac.Ingress(request.Name, request.Namespace).
-		WithAnnotations(map[string]string{
-			"kaoto.io/ingress": endpoint.String(),
-		})
-
-	_, err = r.client.NetworkingV1().Ingresses(kaotoNamespaceName).
-		Apply(ctx, ingressConfig, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
-	if err != nil {
+	if err := r.client.Patch(ctx, object, patch); err != nil {
 		return reconcile.Result{}, err
 	}
 
 	return reconcile.Result{}, nil
 }
+
+// kaotoEndpoint returns the URL the Kaoto UI is served from, or an empty string if it cannot be resolved yet.
+func kaotoEndpoint(kaoto *config.KaotoSpec, object ctrl.Object) string {
+	host := kaoto.Ingress.Host
+	if host == "" {
+		switch o := object.(type) {
+		case *networkingv1.Ingress:
+			if len(o.Status.LoadBalancer.Ingress) > 0 {
+				if lb := o.Status.LoadBalancer.Ingress[0]; lb.Hostname != "" {
+					host = lb.Hostname
+				} else {
+					host = lb.IP
+				}
+			}
+		case *unstructured.Unstructured:
+			if o.GroupVersionKind() == routeGVK {
+				ingresses, _, _ := unstructured.NestedSlice(o.Object, "status", "ingress")
+				if len(ingresses) > 0 {
+					if ingress, ok := ingresses[0].(map[string]interface{}); ok {
+						host, _, _ = unstructured.NestedString(ingress, "host")
+					}
+				}
+			}
+		}
+	}
+	if host == "" {
+		return ""
+	}
+
+	scheme := "http"
+	if kaoto.Ingress.TLS != nil {
+		scheme = "https"
+	}
+
+	endpoint := url.URL{
+		Scheme: scheme,
+		Host:   host,
+		Path:   logicalcluster.From(object).String() + "/kaoto",
+	}
+	return endpoint.String()
+}
+
+func kaotoIngressGVK(kaoto *config.KaotoSpec) schema.GroupVersionKind {
+	switch kaoto.Ingress.ProfileOrDefault() {
+	case config.IngressProfileOpenShiftRoute:
+		return routeGVK
+	case config.IngressProfileGatewayHTTPRoute:
+		return httpRouteGVK
+	default:
+		return networkingv1.SchemeGroupVersion.WithKind("Ingress")
+	}
+}
+
+func kaotoIngressKind(kaoto *config.KaotoSpec) string {
+	return kaotoIngressGVK(kaoto).Kind
+}
+
+// KaotoIngressObject returns an empty object of the resource exposing the Kaoto UI, for the configured profile.
+func KaotoIngressObject(kaoto *config.KaotoSpec) ctrl.Object {
+	switch kaoto.Ingress.ProfileOrDefault() {
+	case config.IngressProfileOpenShiftRoute, config.IngressProfileGatewayHTTPRoute:
+		object := &unstructured.Unstructured{}
+		object.SetGroupVersionKind(kaotoIngressGVK(kaoto))
+		return object
+	default:
+		return &networkingv1.Ingress{}
+	}
+}
+
+// applyKaotoIngress exposes the Kaoto UI under the /<logical-cluster>/kaoto path, with the configured profile.
+func (r *reconciler) applyKaotoIngress(ctx context.Context, kaoto *config.KaotoSpec, cluster string, labels map[string]string) error {
+	path := "/" + cluster + "/kaoto"
+
+	switch kaoto.Ingress.ProfileOrDefault() {
+	case config.IngressProfileOpenShiftRoute:
+		return r.apply(ctx, kaotoRoute(kaoto, path, labels))
+
+	case config.IngressProfileGatewayHTTPRoute:
+		if kaoto.Ingress.Gateway == nil {
+			return errors.New("the Gateway must be configured for the GatewayHTTPRoute ingress profile")
+		}
+		return r.apply(ctx, kaotoHTTPRoute(kaoto, path, labels))
+
+	default:
+		ingressPath, annotations := kaotoIngressPath(kaoto, path)
+		ingressSpec := networkingv1ac.IngressSpec()
+		if kaoto.Ingress.IngressClassName != nil {
+			ingressSpec.WithIngressClassName(*kaoto.Ingress.IngressClassName)
+		}
+		if tls := kaoto.Ingress.TLS; tls != nil {
+			ingressTLS := networkingv1ac.IngressTLS()
+			if kaoto.Ingress.Host != "" {
+				ingressTLS.WithHosts(kaoto.Ingress.Host)
+			}
+			if tls.SecretName != "" {
+				ingressTLS.WithSecretName(tls.SecretName)
+			}
+			ingressSpec.WithTLS(ingressTLS)
+		}
+		ingressRule := networkingv1ac.IngressRule()
+		if kaoto.Ingress.Host != "" {
+			ingressRule.WithHost(kaoto.Ingress.Host)
+		}
+		ingress := networkingv1ac.Ingress("kaoto", config.KaotoNamespace).
+			WithLabels(labels).
+			WithAnnotations(annotations).
+			WithSpec(ingressSpec.
+				WithRules(ingressRule.
+					WithHTTP(networkingv1ac.HTTPIngressRuleValue().
+						WithPaths(networkingv1ac.HTTPIngressPath().
+							WithPath(ingressPath).
+							WithPathType(networkingv1.PathTypePrefix).
+							WithBackend(networkingv1ac.IngressBackend().
+								WithService(networkingv1ac.IngressServiceBackend().
+									WithName("kaoto-ui").
+									WithPort(networkingv1ac.ServiceBackendPort().
+										WithName("http"))))))))
+		_, err := r.client.NetworkingV1().Ingresses(config.KaotoNamespace).
+			Apply(ctx, ingress, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
+		return err
+	}
+}
+
+// kaotoIngressPath returns the path and the annotations of the Ingress exposing the Kaoto UI under the given path.
+// The path is a regular expression, whose prefix is rewritten by the default annotations, when the ingress class
+// is nginx, and no annotations are configured. Otherwise, it's a plain prefix, and the rewrite of the path, if any,
+// is left to the configured annotations.
+func kaotoIngressPath(kaoto *config.KaotoSpec, path string) (string, map[string]string) {
+	if kaoto.Ingress.Annotations != nil {
+		return path, kaoto.Ingress.Annotations
+	}
+	if class := kaoto.Ingress.IngressClassName; class != nil && *class != nginxIngressClassName {
+		return path, nil
+	}
+	return path + "(/|$)(.*)", map[string]string{
+		"nginx.ingress.kubernetes.io/use-regex":      "true",
+		"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
+	}
+}
+
+func kaotoRoute(kaoto *config.KaotoSpec, path string, labels map[string]string) *unstructured.Unstructured {
+	annotations := kaoto.Ingress.Annotations
+	if annotations == nil {
+		annotations = map[string]string{
+			"haproxy.router.openshift.io/rewrite-target": "/",
+		}
+	}
+
+	spec := map[string]interface{}{
+		"path": path,
+		"to": map[string]interface{}{
+			"kind": "Service",
+			"name": "kaoto-ui",
+		},
+		"port": map[string]interface{}{
+			"targetPort": "http",
+		},
+	}
+	if kaoto.Ingress.Host != "" {
+		spec["host"] = kaoto.Ingress.Host
+	}
+	if kaoto.Ingress.TLS != nil {
+		spec["tls"] = map[string]interface{}{
+			"termination":                   "edge",
+			"insecureEdgeTerminationPolicy": "Redirect",
+		}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(routeGVK)
+	route.SetNamespace(config.KaotoNamespace)
+	route.SetName("kaoto")
+	route.SetLabels(labels)
+	route.SetAnnotations(annotations)
+	return route
+}
+
+func kaotoHTTPRoute(kaoto *config.KaotoSpec, path string, labels map[string]string) *unstructured.Unstructured {
+	gateway := kaoto.Ingress.Gateway
+	parentRef := map[string]interface{}{
+		"name": gateway.Name,
+	}
+	if gateway.Namespace != "" {
+		parentRef["namespace"] = gateway.Namespace
+	}
+	if gateway.SectionName != "" {
+		parentRef["sectionName"] = gateway.SectionName
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  "PathPrefix",
+							"value": path,
+						},
+					},
+				},
+				"filters": []interface{}{
+					map[string]interface{}{
+						"type": "URLRewrite",
+						"urlRewrite": map[string]interface{}{
+							"path": map[string]interface{}{
+								"type":               "ReplacePrefixMatch",
+								"replacePrefixMatch": "/",
+							},
+						},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": "kaoto-ui",
+						"port": int64(80),
+					},
+				},
+			},
+		},
+	}
+	if kaoto.Ingress.Host != "" {
+		spec["hostnames"] = []interface{}{kaoto.Ingress.Host}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(httpRouteGVK)
+	route.SetNamespace(config.KaotoNamespace)
+	route.SetName("kaoto")
+	route.SetLabels(labels)
+	if annotations := kaoto.Ingress.Annotations; annotations != nil {
+		route.SetAnnotations(annotations)
+	}
+	return route
+}
+
+// deleteKaotoIngress deletes the resource exposing the Kaoto UI, for the configured profile.
+func (r *reconciler) deleteKaotoIngress(kaoto *config.KaotoSpec) deleteFunc {
+	return func(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+		object := KaotoIngressObject(kaoto)
+		object.SetNamespace(config.KaotoNamespace)
+		object.SetName(name)
+		err := r.client.Delete(ctx, object, &ctrl.DeleteOptions{Raw: &opts})
+		if meta.IsNoMatchError(err) {
+			// The API is not bound into the consumer workspace
+			return nil
+		}
+		return err
+	}
+}
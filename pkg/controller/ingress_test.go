@@ -0,0 +1,179 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+func TestKaotoEndpoint(t *testing.T) {
+	ingress := func(lb ...corev1.LoadBalancerIngress) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "kaoto",
+				Namespace:   "kaoto",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "tenant"},
+			},
+			Status: networkingv1.IngressStatus{
+				LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: lb,
+				},
+			},
+		}
+	}
+	route := func(host string) *unstructured.Unstructured {
+		route := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		route.SetGroupVersionKind(routeGVK)
+		route.SetAnnotations(map[string]string{logicalcluster.AnnotationKey: "tenant"})
+		if host != "" {
+			route.Object["status"] = map[string]interface{}{
+				"ingress": []interface{}{map[string]interface{}{"host": host}},
+			}
+		}
+		return route
+	}
+
+	tests := []struct {
+		name     string
+		ingress  config.KaotoIngress
+		object   ctrl.Object
+		endpoint string
+	}{
+		{
+			name:     "load balancer host name",
+			object:   ingress(corev1.LoadBalancerIngress{Hostname: "lb.example.com", IP: "10.0.0.1"}),
+			endpoint: "http://lb.example.com/tenant/kaoto",
+		},
+		{
+			name:     "load balancer IP",
+			object:   ingress(corev1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			endpoint: "http://10.0.0.1/tenant/kaoto",
+		},
+		{
+			name:     "load balancer not provisioned",
+			object:   ingress(),
+			endpoint: "",
+		},
+		{
+			name:     "configured host",
+			ingress:  config.KaotoIngress{Host: "kaoto.example.com"},
+			object:   ingress(corev1.LoadBalancerIngress{Hostname: "lb.example.com"}),
+			endpoint: "http://kaoto.example.com/tenant/kaoto",
+		},
+		{
+			name:     "configured host with TLS",
+			ingress:  config.KaotoIngress{Host: "kaoto.example.com", TLS: &config.KaotoIngressTLS{}},
+			object:   ingress(),
+			endpoint: "https://kaoto.example.com/tenant/kaoto",
+		},
+		{
+			name:     "load balancer IP with TLS",
+			ingress:  config.KaotoIngress{TLS: &config.KaotoIngressTLS{SecretName: "kaoto-tls"}},
+			object:   ingress(corev1.LoadBalancerIngress{IP: "10.0.0.1"}),
+			endpoint: "https://10.0.0.1/tenant/kaoto",
+		},
+		{
+			name:     "generated route host",
+			ingress:  config.KaotoIngress{Profile: config.IngressProfileOpenShiftRoute},
+			object:   route("kaoto-kaoto.apps.example.com"),
+			endpoint: "http://kaoto-kaoto.apps.example.com/tenant/kaoto",
+		},
+		{
+			name:     "route host not generated",
+			ingress:  config.KaotoIngress{Profile: config.IngressProfileOpenShiftRoute},
+			object:   route(""),
+			endpoint: "",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kaoto := &config.KaotoSpec{Ingress: test.ingress}
+			g.Expect(kaotoEndpoint(kaoto, test.object)).To(Equal(test.endpoint))
+		})
+	}
+}
+
+func TestKaotoIngressPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		ingress     config.KaotoIngress
+		path        string
+		annotations map[string]string
+	}{
+		{
+			name: "default ingress class",
+			path: "/tenant/kaoto(/|$)(.*)",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/use-regex":      "true",
+				"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
+			},
+		},
+		{
+			name:    "nginx ingress class",
+			ingress: config.KaotoIngress{IngressClassName: pointer.String("nginx")},
+			path:    "/tenant/kaoto(/|$)(.*)",
+			annotations: map[string]string{
+				"nginx.ingress.kubernetes.io/use-regex":      "true",
+				"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
+			},
+		},
+		{
+			name:    "other ingress class",
+			ingress: config.KaotoIngress{IngressClassName: pointer.String("traefik")},
+			path:    "/tenant/kaoto",
+		},
+		{
+			name: "configured annotations",
+			ingress: config.KaotoIngress{
+				IngressClassName: pointer.String("nginx"),
+				Annotations:      map[string]string{"example.com/annotation": "value"},
+			},
+			path:        "/tenant/kaoto",
+			annotations: map[string]string{"example.com/annotation": "value"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			path, annotations := kaotoIngressPath(&config.KaotoSpec{Ingress: test.ingress}, "/tenant/kaoto")
+			g.Expect(path).To(Equal(test.path))
+			g.Expect(annotations).To(Equal(test.annotations))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/tenancy/initialization"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+
+	"github.com/apache/camel-k/pkg/util/log"
+	"github.com/apache/camel-k/pkg/util/monitoring"
+
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+// AddInitializerController adds the controller that releases the initializing consumer workspaces,
+// once the resources configured on APIBinding are provisioned and ready. It must be added to a manager
+// for the initializing workspaces virtual workspace of a WorkspaceType that declares the given initializer.
+// It records the paths of the initializing workspaces, that the overrides can select the workspaces by.
+func AddInitializerController(mgr manager.Manager, cfg *config.Holder, paths *WorkspacePaths, initializer corev1alpha1.LogicalClusterInitializer) error {
+	return builder.ControllerManagedBy(mgr).
+		Named("initializer-controller").
+		For(&corev1alpha1.LogicalCluster{}, builder.WithPredicates(
+			predicate.NewPredicateFuncs(func(obj ctrl.Object) bool {
+				lc, ok := obj.(*corev1alpha1.LogicalCluster)
+				return ok && initialization.InitializerPresent(initializer, lc.Status.Initializers)
+			}))).
+		Complete(monitoring.NewInstrumentedReconciler(
+			newInstrumentedReconciler("initializer-controller", &initializerReconciler{
+				cfg:         cfg,
+				client:      mgr.GetClient(),
+				reader:      mgr.GetAPIReader(),
+				paths:       paths,
+				initializer: initializer,
+			}),
+			schema.GroupVersionKind{
+				Group:   corev1alpha1.SchemeGroupVersion.Group,
+				Version: corev1alpha1.SchemeGroupVersion.Version,
+				Kind:    "LogicalCluster",
+			},
+		))
+}
+
+var initializerLog = log.Log.WithName("controller").WithName("initializer")
+
+type initializerReconciler struct {
+	cfg         *config.Holder
+	client      ctrl.Client
+	reader      ctrl.Reader
+	paths       *WorkspacePaths
+	initializer corev1alpha1.LogicalClusterInitializer
+}
+
+// +kubebuilder:rbac:groups="tenancy.kcp.io",resources=workspacetypes,verbs=initialize
+
+func (r *initializerReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	rlog := initializerLog.WithValues("initializer", r.initializer, "logical-cluster", request.ClusterName)
+	rlog.Info("Reconciling LogicalCluster")
+
+	// Add the logical cluster to the context
+	ctx = kontext.WithCluster(ctx, logicalcluster.Name(request.ClusterName))
+
+	lc := &corev1alpha1.LogicalCluster{}
+	if err := r.client.Get(ctx, request.NamespacedName, lc); err != nil {
+		return reconcile.Result{}, ctrl.IgnoreNotFound(err)
+	}
+	if !initialization.InitializerPresent(r.initializer, lc.Status.Initializers) {
+		return reconcile.Result{}, nil
+	}
+
+	// The path is recorded first, so that it is known when the default APIBindings are provisioned
+	if path, ok := lc.Annotations[core.LogicalClusterPathAnnotationKey]; ok && path != "" {
+		if err := r.paths.record(ctx, logicalcluster.Name(request.ClusterName), logicalcluster.NewPath(path)); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// The default APIBindings are created by the APIBinding initializer
+	if initialization.InitializerPresent(tenancyv1alpha1.WorkspaceAPIBindingsInitializer, lc.Status.Initializers) {
+		rlog.Debug("Waiting for the default APIBindings")
+		return reconcile.Result{RequeueAfter: notReadyRequeueDelay}, nil
+	}
+
+	ready, err := r.provisioned(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !ready {
+		rlog.Debug("Waiting for the APIBindings to be provisioned")
+		return reconcile.Result{RequeueAfter: notReadyRequeueDelay}, nil
+	}
+
+	rlog.Info("Releasing LogicalCluster")
+	patch := ctrl.MergeFromWithOptions(lc.DeepCopy(), ctrl.MergeFromWithOptimisticLock{})
+	lc.Status.Initializers = initialization.EnsureInitializerAbsent(r.initializer, lc.Status.Initializers)
+	return reconcile.Result{}, r.client.Status().Patch(ctx, lc, patch)
+}
+
+// provisioned returns whether the APIBindings to the camel-kcp APIExports, in the logical cluster of the context,
+// are bound, and all their provisioning conditions are true.
+func (r *initializerReconciler) provisioned(ctx context.Context) (bool, error) {
+	apiExports := &r.cfg.Get().Service.APIExports
+	exports := map[string]bool{
+		apiExports.CamelK.APIExportName: true,
+		apiExports.Kaoto.APIExportName:  true,
+	}
+	for _, addOn := range apiExports.AddOns {
+		exports[addOn.APIExportName] = true
+	}
+
+	// Use the non-caching client, as the APIBindings are only listed during initialization
+	bindings := &apisv1alpha1.APIBindingList{}
+	if err := r.reader.List(ctx, bindings); err != nil {
+		return false, err
+	}
+
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		if export := binding.Spec.Reference.Export; export == nil || !exports[export.Name] {
+			continue
+		}
+		if binding.Status.Phase != apisv1alpha1.APIBindingPhaseBound || !isReady(binding) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
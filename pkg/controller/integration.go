@@ -0,0 +1,112 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+)
+
+// AddIntegrationMetricsController adds the controller that counts the Integrations by phase,
+// across all the consumer workspaces.
+func AddIntegrationMetricsController(mgr manager.Manager) error {
+	return builder.ControllerManagedBy(mgr).
+		Named("integration-metrics-controller").
+		For(&camelv1.Integration{}, builder.WithPredicates(
+			predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					oldIntegration, ok := e.ObjectOld.(*camelv1.Integration)
+					if !ok {
+						return false
+					}
+					newIntegration, ok := e.ObjectNew.(*camelv1.Integration)
+					if !ok {
+						return false
+					}
+					return oldIntegration.Status.Phase != newIntegration.Status.Phase
+				},
+			})).
+		Complete(&integrationMetricsReconciler{
+			client: mgr.GetClient(),
+		})
+}
+
+type integrationMetricsReconciler struct {
+	client ctrl.Client
+}
+
+func (r *integrationMetricsReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// Add the logical cluster to the context
+	ctx = kontext.WithCluster(ctx, logicalcluster.Name(request.ClusterName))
+
+	key := request.ClusterName + "|" + request.NamespacedName.String()
+
+	integration := &camelv1.Integration{}
+	if err := r.client.Get(ctx, request.NamespacedName, integration); err != nil {
+		if ctrl.IgnoreNotFound(err) == nil {
+			integrations.set(key, camelv1.IntegrationPhaseNone)
+		}
+		return reconcile.Result{}, ctrl.IgnoreNotFound(err)
+	}
+
+	integrations.set(key, integration.Status.Phase)
+
+	return reconcile.Result{}, nil
+}
+
+// integrationPhases tracks the phases of the Integrations, and counts them by phase.
+type integrationPhases struct {
+	lock   sync.Mutex
+	phases map[string]camelv1.IntegrationPhase
+}
+
+var integrations = &integrationPhases{
+	phases: make(map[string]camelv1.IntegrationPhase),
+}
+
+// set records the phase of the Integration with the given key. The none phase removes the Integration,
+// so that the Integrations are only counted once they are initialized.
+func (p *integrationPhases) set(key string, phase camelv1.IntegrationPhase) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	old, ok := p.phases[key]
+	if ok && old == phase {
+		return
+	}
+	if ok {
+		integrationsGauge.WithLabelValues(string(old)).Dec()
+		delete(p.phases, key)
+	}
+	if phase != camelv1.IntegrationPhaseNone {
+		p.phases[key] = phase
+		integrationsGauge.WithLabelValues(string(phase)).Inc()
+	}
+}
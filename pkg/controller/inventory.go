@@ -0,0 +1,109 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+// ManifestsLabel is the label of the manifests inventory ConfigMaps, set to the name of the bound APIExport.
+const ManifestsLabel = "camel-kcp.apache.org/manifests"
+
+const manifestsKey = "manifests"
+
+// ManifestInventory records the inventory of the resources applied from the manifests, for each APIBinding,
+// into the service workspace, so that the resources whose manifests are removed are pruned, and the resources
+// are deleted when the APIExport is unbound, whatever the manifests are at that time. The inventory is not
+// recorded in the consumer workspace, where the tenants could change it. The inventory of each APIBinding
+// is stored into a ConfigMap, next to its status ConfigMap, e.g.:
+//
+//	kubectl get configmaps -n camel-kcp -l camel-kcp.apache.org/manifests=hawtio
+type ManifestInventory struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewManifestInventory returns a ManifestInventory that stores the inventories into the given namespace,
+// using a client for the service workspace.
+func NewManifestInventory(client kubernetes.Interface, namespace string) *ManifestInventory {
+	return &ManifestInventory{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;patch;delete
+
+// get returns the inventory of the resources applied from the manifests, and whether it's recorded.
+func (i *ManifestInventory) get(ctx context.Context, apiExport string, cluster logicalcluster.Name) ([]manifestObject, bool, error) {
+	configMap, err := i.client.CoreV1().ConfigMaps(i.namespace).Get(ctx, manifestsConfigMapName(apiExport, cluster), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("error reading manifests inventory from the service workspace: %w", err)
+	}
+	var inventory []manifestObject
+	if err := json.Unmarshal([]byte(configMap.Data[manifestsKey]), &inventory); err != nil {
+		return nil, true, fmt.Errorf("invalid manifests inventory %s/%s: %w", configMap.Namespace, configMap.Name, err)
+	}
+	return inventory, true, nil
+}
+
+// record records the inventory of the resources applied from the manifests, or removes it when it's empty.
+func (i *ManifestInventory) record(ctx context.Context, apiExport string, cluster logicalcluster.Name, inventory []manifestObject) error {
+	name := manifestsConfigMapName(apiExport, cluster)
+	if len(inventory) == 0 {
+		err := i.client.CoreV1().ConfigMaps(i.namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("error removing manifests inventory from the service workspace: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return err
+	}
+	configMap := corev1ac.ConfigMap(name, i.namespace).
+		WithLabels(map[string]string{
+			ManifestsLabel:      apiExport,
+			LogicalClusterLabel: cluster.String(),
+		}).
+		WithData(map[string]string{
+			manifestsKey: string(data),
+		})
+	_, err = i.client.CoreV1().ConfigMaps(i.namespace).
+		Apply(ctx, configMap, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
+	if err != nil {
+		return fmt.Errorf("error recording manifests inventory into the service workspace: %w", err)
+	}
+	return nil
+}
+
+func manifestsConfigMapName(apiExport string, cluster logicalcluster.Name) string {
+	return statusConfigMapName(apiExport, cluster) + "-manifests"
+}
@@ -20,41 +20,57 @@ package controller
 import (
 	"context"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
 	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
-	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
 	rbacv1ac "k8s.io/client-go/applyconfigurations/rbac/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/kontext"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/util/log"
 	"github.com/apache/camel-k/pkg/util/monitoring"
 
 	"github.com/apache/camel-kcp/pkg/client"
 	"github.com/apache/camel-kcp/pkg/config"
-	"github.com/apache/camel-kcp/pkg/platform"
 )
 
-const kaotoNamespaceName = "kaoto"
+const (
+	kaotoFinalizer = "camel-kcp.apache.org/kaoto"
+	// kaotoAPIBindingLabel is the label of the Kaoto resources, set to the name of the APIBinding they are
+	// provisioned for, so that the APIBinding is reconciled when they are changed or deleted.
+	kaotoAPIBindingLabel = "camel-kcp.apache.org/kaoto-api-binding"
 
-func AddKaotoController(mgr manager.Manager, c client.Client, cfg *config.ServiceConfiguration) error {
-	return builder.ControllerManagedBy(mgr).
+	defaultKaotoUIImage      = "ghcr.io/astefanutti/kaoto-ui:latest"
+	defaultKaotoBackendImage = "ghcr.io/astefanutti/kaoto-backend:latest"
+)
+
+func AddKaotoController(mgr manager.Manager, c client.Client, cfg *config.Holder, status *StatusReporter, paths *WorkspacePaths, inventory *ManifestInventory) error {
+	b := builder.ControllerManagedBy(mgr).
 		Named("kaoto-apibinding-controller").
 		For(&apisv1alpha1.APIBinding{}, builder.WithPredicates(
 			predicate.Funcs{
@@ -64,8 +80,8 @@ func AddKaotoController(mgr manager.Manager, c client.Client, cfg *config.Servic
 					if !ok {
 						return false
 					}
-					if binding.DeletionTimestamp != nil && !binding.DeletionTimestamp.IsZero() {
-						return false
+					if isDeleted(binding) {
+						return controllerutil.ContainsFinalizer(binding, kaotoFinalizer)
 					}
 					return binding.Status.Phase == apisv1alpha1.APIBindingPhaseBound
 				},
@@ -73,20 +89,98 @@ func AddKaotoController(mgr manager.Manager, c client.Client, cfg *config.Servic
 					return false
 				},
 			})).
-		Complete(monitoring.NewInstrumentedReconciler(
-			&kaotoReconciler{
-				reconciler{
-					cfg:      cfg,
-					client:   c,
-					recorder: mgr.GetEventRecorderFor("kaoto-apibinding-controller"),
-				},
-			},
-			schema.GroupVersionKind{
-				Group:   apisv1alpha1.SchemeGroupVersion.Group,
-				Version: apisv1alpha1.SchemeGroupVersion.Version,
-				Kind:    "APIBinding",
+		Watches(&configurationReloaded{cfg: cfg, client: mgr.GetClient()}, &handler.EnqueueRequestForObject{})
+
+	// Watch the Kaoto resources, so that they are re-applied when they drift from the configuration.
+	// The ingress profile cannot be changed without restarting.
+	for _, object := range kaotoResources(&cfg.Get().Service.APIExports.Kaoto.OnAPIBinding.Kaoto) {
+		b = b.Watches(&source.Kind{Type: object}, handler.EnqueueRequestsFromMapFunc(kaotoAPIBindingRequest),
+			builder.WithPredicates(kaotoResourceChanged))
+	}
+
+	return b.Complete(monitoring.NewInstrumentedReconciler(
+		newInstrumentedReconciler("kaoto-apibinding-controller", &kaotoReconciler{
+			reconciler{
+				cfg:       cfg,
+				client:    c,
+				recorder:  mgr.GetEventRecorderFor("kaoto-apibinding-controller"),
+				status:    status,
+				paths:     paths,
+				inventory: inventory,
 			},
-		))
+		}),
+		schema.GroupVersionKind{
+			Group:   apisv1alpha1.SchemeGroupVersion.Group,
+			Version: apisv1alpha1.SchemeGroupVersion.Version,
+			Kind:    "APIBinding",
+		},
+	))
+}
+
+// KaotoCacheSelectors returns the selectors of the Kaoto manager cache, so that only the Kaoto resources,
+// amongst the resources of the kinds watched for drift, are cached across the consumer workspaces.
+func KaotoCacheSelectors(kaoto *config.KaotoSpec) (cache.SelectorsByObject, error) {
+	hasAPIBindingLabel, err := labels.NewRequirement(kaotoAPIBindingLabel, selection.Exists, []string{})
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.NewSelector().Add(*hasAPIBindingLabel)
+	selectors := cache.SelectorsByObject{}
+	for _, object := range kaotoResources(kaoto) {
+		selectors[object] = cache.ObjectSelector{Label: selector}
+	}
+	return selectors, nil
+}
+
+// kaotoResources returns the kinds of the Kaoto resources, all labelled with the APIBinding they are provisioned for.
+func kaotoResources(kaoto *config.KaotoSpec) []ctrl.Object {
+	return []ctrl.Object{
+		&appsv1.Deployment{},
+		&corev1.Service{},
+		&corev1.ServiceAccount{},
+		&rbacv1.ClusterRole{},
+		&rbacv1.ClusterRoleBinding{},
+		KaotoIngressObject(kaoto),
+	}
+}
+
+// kaotoAPIBindingRequest maps the Kaoto resources to the request of the APIBinding they are provisioned for.
+func kaotoAPIBindingRequest(object ctrl.Object) []reconcile.Request {
+	name := object.GetLabels()[kaotoAPIBindingLabel]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{Name: name},
+			ClusterName:    logicalcluster.From(object).String(),
+		},
+	}
+}
+
+// kaotoResourceChanged filters the events of the Kaoto resources, that may have drifted from the configuration.
+// The resources are created by the reconciler itself, and the status updates are ignored, for the resources
+// whose generation is incremented when their spec changes.
+var kaotoResourceChanged = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		// The label may have been removed, in which case the previous state is mapped to the APIBinding
+		if e.ObjectOld.GetLabels()[kaotoAPIBindingLabel] == "" {
+			return false
+		}
+		return e.ObjectNew.GetGeneration() == 0 ||
+			e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration() ||
+			!equality.Semantic.DeepEqual(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels()) ||
+			!equality.Semantic.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return e.Object.GetLabels()[kaotoAPIBindingLabel] != ""
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
 }
 
 type kaotoReconciler struct {
@@ -100,40 +194,103 @@ func (r *kaotoReconciler) Reconcile(ctx context.Context, request reconcile.Reque
 	// Add the logical cluster to the context
 	ctx = kontext.WithCluster(ctx, logicalcluster.Name(request.ClusterName))
 
-	if placement := r.cfg.Service.APIExports.Kaoto.OnAPIBinding.DefaultPlacement; placement != nil {
-		if err := r.maybeCreatePlacement(ctx, placement); err != nil {
-			return reconcile.Result{}, err
-		}
+	// Use the same configuration for the whole reconciliation
+	svcCfg := r.cfg.Get()
+	apiExport := &svcCfg.Service.APIExports.Kaoto
+	ingressHost := apiExport.OnAPIBinding.Kaoto.Ingress.Host
+	if ingressHost == "" {
+		ingressHost = svcCfg.Service.IngressHost
 	}
 
-	if err := r.maybeCreateNamespace(ctx, kaotoNamespaceName); err != nil {
-		if errors.IsNotFound(err) {
-			rlog.Debug("Bound APIs are not yet found")
-			return reconcile.Result{Requeue: true}, nil
-		}
+	binding := &apisv1alpha1.APIBinding{}
+	if err := r.client.Get(ctx, request.NamespacedName, binding); err != nil {
+		return reconcile.Result{}, ctrl.IgnoreNotFound(err)
+	}
+
+	if isDeleted(binding) {
+		rlog.Info("Cleaning up APIBinding")
+		return reconcile.Result{}, r.cleanup(ctx, apiExport, binding, ingressHost)
+	}
+
+	if err := r.addFinalizer(ctx, binding, kaotoFinalizer); err != nil {
 		return reconcile.Result{}, err
 	}
+	workspaces.add(apiExport.APIExportName, logicalcluster.From(binding), maxLogicalClusters(r.cfg))
 
-	if err := r.applyKaotoResources(ctx, request, platform.DefaultNamespaceName); err != nil {
-		if errors.IsNotFound(err) {
-			rlog.Debug("Bound APIs are not yet found")
-			return reconcile.Result{Requeue: true}, nil
+	conditions := conditionsOf(binding)
+	result, err := r.provision(ctx, rlog, request, apiExport, &svcCfg.Service.APIExports.CamelK.OnAPIBinding, binding, ingressHost, &conditions)
+	if statusErr := r.reportStatus(ctx, binding, apiExport.APIExportName, conditions); statusErr != nil {
+		rlog.Error(statusErr, "Error reporting APIBinding status")
+		if err == nil {
+			return reconcile.Result{}, statusErr
 		}
+	}
+
+	return result, err
+}
+
+// provision creates the resources in the consumer workspace, and sets the conditions accordingly.
+func (r *kaotoReconciler) provision(ctx context.Context, rlog log.Logger, request reconcile.Request, apiExport *config.KaotoAPIExport, camelKConfig *config.OnCamelKAPIBinding, binding *apisv1alpha1.APIBinding, ingressHost string, conditions *[]metav1.Condition) (reconcile.Result, error) {
+	path, err := r.paths.get(ctx, logicalcluster.From(binding))
+	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	// The provisioning carries on when the placement is not ready yet, and the request is requeued at the end
+	var notReadyErr error
+
+	if placement := apiExport.OnAPIBinding.DefaultPlacement; placement != nil {
+		err := r.applyPlacement(ctx, placement)
+		setCondition(conditions, binding, PlacementReady, err)
+		if isNotReady(err) {
+			notReadyErr = err
+		} else if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// The Camel K operator namespace is resolved as the Camel K APIBinding reconciler does
+	onCamelKBinding, err := camelKConfig.ForWorkspace(logicalcluster.From(binding).String(), path.String())
+	var camelKNamespace string
+	if err == nil {
+		camelKNamespace = operatorNamespace(onCamelKBinding)
+		err = r.maybeCreateNamespace(ctx, config.KaotoNamespace, apiExport.APIExportName)
+	}
+	if err == nil {
+		err = r.applyKaotoResources(ctx, request, &apiExport.OnAPIBinding.Kaoto, camelKNamespace)
+	}
+	setCondition(conditions, binding, KaotoReady, err)
+	if err != nil {
+		return requeueIfNotFound(rlog, err)
+	}
+
+	// The resources of the manifests that are removed from the configuration are pruned
+	if manifests := apiExport.OnAPIBinding.Manifests; len(manifests) > 0 || meta.FindStatusCondition(*conditions, ManifestsReady) != nil {
+		err := r.applyManifests(ctx, binding, apiExport.APIExportName, &apiExport.OnAPIUnbinding, manifests, manifestValues(binding, path, ingressHost))
+		setCondition(conditions, binding, ManifestsReady, err)
+		if err != nil {
+			return requeueIfNotFound(rlog, err)
+		}
+		if len(manifests) == 0 {
+			meta.RemoveStatusCondition(conditions, ManifestsReady)
+		}
+	}
+
+	return requeueIfNotFound(rlog, notReadyErr)
 }
 
-func (r *kaotoReconciler) applyKaotoResources(ctx context.Context, request reconcile.Request, camelNamespaceName string) error {
-	serviceAccount := corev1ac.ServiceAccount("kaoto", kaotoNamespaceName)
+func (r *kaotoReconciler) applyKaotoResources(ctx context.Context, request reconcile.Request, kaoto *config.KaotoSpec, camelNamespaceName string) error {
+	kaotoNamespaceName := config.KaotoNamespace
+	labels := map[string]string{kaotoAPIBindingLabel: request.Name}
+
+	serviceAccount := corev1ac.ServiceAccount("kaoto", kaotoNamespaceName).WithLabels(labels)
 	_, err := r.client.CoreV1().ServiceAccounts(kaotoNamespaceName).
 		Apply(ctx, serviceAccount, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
 	if err != nil {
 		return err
 	}
 
-	clusterRole := rbacv1ac.ClusterRole("kaoto").WithRules(
+	clusterRole := rbacv1ac.ClusterRole("kaoto").WithLabels(labels).WithRules(
 		rbacv1ac.PolicyRule().
 			WithAPIGroups(camelv1.SchemeGroupVersion.Group).
 			WithResources("integrations", "kameletbindings", "kamelets").
@@ -154,6 +311,7 @@ func (r *kaotoReconciler) applyKaotoResources(ctx context.Context, request recon
 	}
 
 	clusterRoleBinding := rbacv1ac.ClusterRoleBinding("kaoto").
+		WithLabels(labels).
 		WithSubjects(rbacv1ac.Subject().
 			WithKind(rbacv1.ServiceAccountKind).
 			WithNamespace(kaotoNamespaceName).
@@ -169,21 +327,13 @@ func (r *kaotoReconciler) applyKaotoResources(ctx context.Context, request recon
 	}
 
 	deploymentKaotoUI := appsv1ac.Deployment("kaoto-ui", kaotoNamespaceName).
+		WithLabels(labels).
 		WithSpec(appsv1ac.DeploymentSpec().
-			WithReplicas(1).
+			WithReplicas(kaotoReplicas(&kaoto.UI)).
 			WithSelector(metav1ac.LabelSelector().WithMatchLabels(map[string]string{"app": "kaoto-ui"})).
 			WithTemplate(corev1ac.PodTemplateSpec().WithLabels(map[string]string{"app": "kaoto-ui"}).
-				WithSpec(corev1ac.PodSpec().WithContainers(
-					corev1ac.Container().
-						WithName("kaoto-ui").
-						WithImage("ghcr.io/astefanutti/kaoto-ui:latest").
-						WithPorts(corev1ac.ContainerPort().
-							WithName("http").
-							WithContainerPort(8080).
-							WithProtocol(corev1.ProtocolTCP)).
-						WithTerminationMessagePolicy(corev1.TerminationMessageReadFile).
-						WithTerminationMessagePath(corev1.TerminationMessagePathDefault)).
-					WithRestartPolicy(corev1.RestartPolicyAlways))))
+				WithSpec(kaotoPodSpec(kaoto).WithContainers(
+					kaotoContainer("kaoto-ui", &kaoto.UI, defaultKaotoUIImage, 8080)))))
 	_, err = r.client.AppsV1().Deployments(kaotoNamespaceName).
 		Apply(ctx, deploymentKaotoUI, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
 	if err != nil {
@@ -191,22 +341,14 @@ func (r *kaotoReconciler) applyKaotoResources(ctx context.Context, request recon
 	}
 
 	deploymentKaotoBackend := appsv1ac.Deployment("kaoto-backend", kaotoNamespaceName).
+		WithLabels(labels).
 		WithSpec(appsv1ac.DeploymentSpec().
-			WithReplicas(1).
+			WithReplicas(kaotoReplicas(&kaoto.Backend)).
 			WithSelector(metav1ac.LabelSelector().WithMatchLabels(map[string]string{"app": "kaoto-backend"})).
 			WithTemplate(corev1ac.PodTemplateSpec().WithLabels(map[string]string{"app": "kaoto-backend"}).
-				WithSpec(corev1ac.PodSpec().WithContainers(
-					corev1ac.Container().
-						WithName("kaoto-backend").
-						WithImage("ghcr.io/astefanutti/kaoto-backend:latest").
-						WithPorts(corev1ac.ContainerPort().
-							WithName("http").
-							WithContainerPort(8081).
-							WithProtocol(corev1.ProtocolTCP)).
-						WithEnv(corev1ac.EnvVar().WithName("CATALOG_NAMESPACE").WithValue(camelNamespaceName)).
-						WithTerminationMessagePolicy(corev1.TerminationMessageReadFile).
-						WithTerminationMessagePath(corev1.TerminationMessagePathDefault)).
-					WithRestartPolicy(corev1.RestartPolicyAlways).
+				WithSpec(kaotoPodSpec(kaoto).
+					WithContainers(kaotoContainer("kaoto-backend", &kaoto.Backend, defaultKaotoBackendImage, 8081,
+						corev1.EnvVar{Name: "CATALOG_NAMESPACE", Value: camelNamespaceName})).
 					WithServiceAccountName("kaoto"))))
 	_, err = r.client.AppsV1().Deployments(kaotoNamespaceName).
 		Apply(ctx, deploymentKaotoBackend, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
@@ -214,7 +356,7 @@ func (r *kaotoReconciler) applyKaotoResources(ctx context.Context, request recon
 		return err
 	}
 
-	serviceKaotoUI := corev1ac.Service("kaoto-ui", kaotoNamespaceName).WithSpec(corev1ac.ServiceSpec().
+	serviceKaotoUI := corev1ac.Service("kaoto-ui", kaotoNamespaceName).WithLabels(labels).WithSpec(corev1ac.ServiceSpec().
 		WithPorts(corev1ac.ServicePort().
 			WithName("http").
 			WithProtocol(corev1.ProtocolTCP).
@@ -229,7 +371,7 @@ func (r *kaotoReconciler) applyKaotoResources(ctx context.Context, request recon
 		return err
 	}
 
-	serviceKaotoBackend := corev1ac.Service("kaoto-backend-svc", kaotoNamespaceName).WithSpec(corev1ac.ServiceSpec().
+	serviceKaotoBackend := corev1ac.Service("kaoto-backend-svc", kaotoNamespaceName).WithLabels(labels).WithSpec(corev1ac.ServiceSpec().
 		WithPorts(corev1ac.ServicePort().
 			WithName("http").
 			WithProtocol(corev1.ProtocolTCP).
@@ -244,27 +386,168 @@ func (r *kaotoReconciler) applyKaotoResources(ctx context.Context, request recon
 		return err
 	}
 
-	ingress := networkingv1ac.Ingress("kaoto", kaotoNamespaceName).
-		WithAnnotations(map[string]string{
-			"nginx.ingress.kubernetes.io/use-regex":      "true",
-			"nginx.ingress.kubernetes.io/rewrite-target": "/$2",
-		}).
-		WithSpec(networkingv1ac.IngressSpec().
-			WithRules(networkingv1ac.IngressRule().
-				WithHTTP(networkingv1ac.HTTPIngressRuleValue().
-					WithPaths(networkingv1ac.HTTPIngressPath().
-						WithPath("/" + request.ClusterName + "/kaoto(/|$)(.*)").
-						WithPathType(networkingv1.PathTypePrefix).
-						WithBackend(networkingv1ac.IngressBackend().
-							WithService(networkingv1ac.IngressServiceBackend().
-								WithName("kaoto-ui").
-								WithPort(networkingv1ac.ServiceBackendPort().
-									WithName("http"))))))))
-	_, err = r.client.NetworkingV1().Ingresses(kaotoNamespaceName).
-		Apply(ctx, ingress, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
+	return r.applyKaotoIngress(ctx, kaoto, request.ClusterName, labels)
+}
+
+func kaotoReplicas(component *config.KaotoComponent) int32 {
+	if component.Replicas != nil {
+		return *component.Replicas
+	}
+	return 1
+}
+
+func kaotoPodSpec(kaoto *config.KaotoSpec) *corev1ac.PodSpecApplyConfiguration {
+	podSpec := corev1ac.PodSpec().
+		WithRestartPolicy(corev1.RestartPolicyAlways)
+	if len(kaoto.NodeSelector) > 0 {
+		podSpec.WithNodeSelector(kaoto.NodeSelector)
+	}
+	for _, secret := range kaoto.ImagePullSecrets {
+		podSpec.WithImagePullSecrets(corev1ac.LocalObjectReference().WithName(secret.Name))
+	}
+	return podSpec
+}
+
+// kaotoContainer returns the container of the given Kaoto component. The default environment variables
+// are overridden by the environment variables with the same name from the component configuration.
+func kaotoContainer(name string, component *config.KaotoComponent, defaultImage string, port int32, defaultEnv ...corev1.EnvVar) *corev1ac.ContainerApplyConfiguration {
+	image := component.Image
+	if image == "" {
+		image = defaultImage
+	}
+	container := corev1ac.Container().
+		WithName(name).
+		WithImage(image).
+		WithPorts(corev1ac.ContainerPort().
+			WithName("http").
+			WithContainerPort(port).
+			WithProtocol(corev1.ProtocolTCP)).
+		WithTerminationMessagePolicy(corev1.TerminationMessageReadFile).
+		WithTerminationMessagePath(corev1.TerminationMessagePathDefault)
+	if component.ImagePullPolicy != "" {
+		container.WithImagePullPolicy(component.ImagePullPolicy)
+	}
+	if len(component.Resources.Limits) > 0 || len(component.Resources.Requests) > 0 {
+		resources := corev1ac.ResourceRequirements()
+		if len(component.Resources.Limits) > 0 {
+			resources.WithLimits(component.Resources.Limits)
+		}
+		if len(component.Resources.Requests) > 0 {
+			resources.WithRequests(component.Resources.Requests)
+		}
+		container.WithResources(resources)
+	}
+
+	env := make(map[string]bool, len(component.Env))
+	for _, e := range component.Env {
+		env[e.Name] = true
+	}
+	for _, e := range defaultEnv {
+		if !env[e.Name] {
+			container.WithEnv(envVar(e))
+		}
+	}
+	for _, e := range component.Env {
+		container.WithEnv(envVar(e))
+	}
+
+	return container
+}
+
+// envVar converts the environment variable into its apply configuration.
+func envVar(e corev1.EnvVar) *corev1ac.EnvVarApplyConfiguration {
+	env := corev1ac.EnvVar().WithName(e.Name)
+	if e.ValueFrom == nil {
+		return env.WithValue(e.Value)
+	}
+	source := corev1ac.EnvVarSource()
+	if ref := e.ValueFrom.FieldRef; ref != nil {
+		field := corev1ac.ObjectFieldSelector().WithFieldPath(ref.FieldPath)
+		if ref.APIVersion != "" {
+			field.WithAPIVersion(ref.APIVersion)
+		}
+		source.WithFieldRef(field)
+	}
+	if ref := e.ValueFrom.ResourceFieldRef; ref != nil {
+		field := corev1ac.ResourceFieldSelector().WithContainerName(ref.ContainerName).WithResource(ref.Resource)
+		if !ref.Divisor.IsZero() {
+			field.WithDivisor(ref.Divisor)
+		}
+		source.WithResourceFieldRef(field)
+	}
+	if ref := e.ValueFrom.ConfigMapKeyRef; ref != nil {
+		selector := corev1ac.ConfigMapKeySelector().WithName(ref.Name).WithKey(ref.Key)
+		if ref.Optional != nil {
+			selector.WithOptional(*ref.Optional)
+		}
+		source.WithConfigMapKeyRef(selector)
+	}
+	if ref := e.ValueFrom.SecretKeyRef; ref != nil {
+		selector := corev1ac.SecretKeySelector().WithName(ref.Name).WithKey(ref.Key)
+		if ref.Optional != nil {
+			selector.WithOptional(*ref.Optional)
+		}
+		source.WithSecretKeyRef(selector)
+	}
+	return env.WithValueFrom(source)
+}
+
+// cleanup deletes the Kaoto resources created in the consumer workspace, according to their cleanup policy,
+// and removes the finalizer from the APIBinding.
+func (r *kaotoReconciler) cleanup(ctx context.Context, apiExport *config.KaotoAPIExport, binding *apisv1alpha1.APIBinding, ingressHost string) error {
+	onUnbinding := &apiExport.OnAPIUnbinding
+
+	path, err := r.paths.get(ctx, logicalcluster.From(binding))
+	if err != nil {
+		return err
+	}
+
+	// The resources of the manifests are deleted first, as they may depend on the other ones
+	err = r.deleteManifests(ctx, binding, apiExport.APIExportName, onUnbinding, apiExport.OnAPIBinding.Manifests, manifestValues(binding, path, ingressHost))
 	if err != nil {
 		return err
 	}
 
+	kaoto := &apiExport.OnAPIBinding.Kaoto
+	kaotoNamespaceName := config.KaotoNamespace
+
+	resources := []struct {
+		kind string
+		name string
+		del  deleteFunc
+	}{
+		{kaotoIngressKind(kaoto), "kaoto", r.deleteKaotoIngress(kaoto)},
+		{"Service", "kaoto-ui", r.client.CoreV1().Services(kaotoNamespaceName).Delete},
+		{"Service", "kaoto-backend-svc", r.client.CoreV1().Services(kaotoNamespaceName).Delete},
+		{"Deployment", "kaoto-ui", r.client.AppsV1().Deployments(kaotoNamespaceName).Delete},
+		{"Deployment", "kaoto-backend", r.client.AppsV1().Deployments(kaotoNamespaceName).Delete},
+		{"ClusterRoleBinding", "kaoto", r.client.RbacV1().ClusterRoleBindings().Delete},
+		{"ClusterRole", "kaoto", r.client.RbacV1().ClusterRoles().Delete},
+		{"ServiceAccount", "kaoto", r.client.CoreV1().ServiceAccounts(kaotoNamespaceName).Delete},
+		{"Namespace", kaotoNamespaceName, r.deleteCreatedNamespace(apiExport.APIExportName)},
+	}
+	if placement := apiExport.OnAPIBinding.DefaultPlacement; placement != nil {
+		resources = append(resources, struct {
+			kind string
+			name string
+			del  deleteFunc
+		}{"Placement", placement.Name, r.client.KcpSchedulingV1alpha1().Placements().Delete})
+	}
+
+	for _, resource := range resources {
+		if err := r.maybeDelete(ctx, onUnbinding, resource.kind, resource.name, resource.del); err != nil {
+			return err
+		}
+	}
+
+	if err := r.status.remove(ctx, apiExport.APIExportName, logicalcluster.From(binding)); err != nil {
+		return err
+	}
+
+	if err := r.removeFinalizer(ctx, binding, kaotoFinalizer); err != nil {
+		return err
+	}
+	workspaces.remove(apiExport.APIExportName, logicalcluster.From(binding))
+
 	return nil
 }
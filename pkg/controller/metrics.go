@@ -0,0 +1,216 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+// otherLogicalClusters is the logical cluster label value of the metrics of the logical clusters,
+// in excess of the maximum number of logical clusters the metrics are labelled with.
+const otherLogicalClusters = "other"
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+	resultRequeue = "requeue"
+)
+
+var (
+	reconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "camel_kcp_reconcile_total",
+			Help: "Total number of reconciliations, by controller, logical cluster and result.",
+		},
+		[]string{"controller", "logical_cluster", "result"},
+	)
+	reconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "camel_kcp_reconcile_errors_total",
+			Help: "Total number of reconciliation errors, by controller and logical cluster.",
+		},
+		[]string{"controller", "logical_cluster"},
+	)
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "camel_kcp_reconcile_duration_seconds",
+			Help:    "Duration of the reconciliations, by controller and logical cluster.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"controller", "logical_cluster"},
+	)
+	boundWorkspacesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "camel_kcp_bound_workspaces",
+			Help: "Number of consumer workspaces bound to the APIExports, by APIExport.",
+		},
+		[]string{"api_export"},
+	)
+	integrationsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "camel_kcp_integrations",
+			Help: "Number of Integrations across all the consumer workspaces, by phase.",
+		},
+		[]string{"phase"},
+	)
+	platformReadyDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "camel_kcp_platform_ready_duration_seconds",
+			Help:    "Duration from the Camel K APIBinding being bound to the default integration platform being ready.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileTotal, reconcileErrors, reconcileDuration,
+		boundWorkspacesGauge, integrationsGauge, platformReadyDuration)
+}
+
+// boundWorkspaces tracks the consumer workspaces bound to each APIExport, and assigns the logical cluster
+// label values of the per-workspace metrics, up to the configured maximum number of logical clusters.
+type boundWorkspaces struct {
+	lock     sync.Mutex
+	clusters map[string]sets.String
+	labelled sets.String
+}
+
+var workspaces = &boundWorkspaces{
+	clusters: make(map[string]sets.String),
+	labelled: sets.NewString(),
+}
+
+// add records the logical cluster as bound to the APIExport. The metrics of the logical cluster are labelled
+// with its name, unless the maximum number of labelled logical clusters has been reached.
+func (w *boundWorkspaces) add(apiExport string, cluster logicalcluster.Name, max int32) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	clusters, ok := w.clusters[apiExport]
+	if !ok {
+		clusters = sets.NewString()
+		w.clusters[apiExport] = clusters
+	}
+	clusters.Insert(cluster.String())
+	boundWorkspacesGauge.WithLabelValues(apiExport).Set(float64(clusters.Len()))
+
+	if !w.labelled.Has(cluster.String()) && w.labelled.Len() < int(max) {
+		w.labelled.Insert(cluster.String())
+	}
+}
+
+// remove records the logical cluster as unbound from the APIExport. The metrics of the logical cluster are
+// deleted once it's bound to none of the APIExports, so that its label value can be assigned to another one.
+func (w *boundWorkspaces) remove(apiExport string, cluster logicalcluster.Name) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if clusters, ok := w.clusters[apiExport]; ok {
+		clusters.Delete(cluster.String())
+		boundWorkspacesGauge.WithLabelValues(apiExport).Set(float64(clusters.Len()))
+	}
+
+	for _, clusters := range w.clusters {
+		if clusters.Has(cluster.String()) {
+			return
+		}
+	}
+	if w.labelled.Has(cluster.String()) {
+		w.labelled.Delete(cluster.String())
+		labels := prometheus.Labels{"logical_cluster": cluster.String()}
+		reconcileTotal.DeletePartialMatch(labels)
+		reconcileErrors.DeletePartialMatch(labels)
+		reconcileDuration.DeletePartialMatch(labels)
+	}
+}
+
+// label returns the logical cluster label value of the per-workspace metrics.
+func (w *boundWorkspaces) label(cluster string) string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.labelled.Has(cluster) {
+		return cluster
+	}
+	return otherLogicalClusters
+}
+
+// instrumentedReconciler records the reconciliation metrics, labelled with the logical cluster of the requests.
+type instrumentedReconciler struct {
+	name       string
+	reconciler reconcile.Reconciler
+}
+
+var _ reconcile.Reconciler = &instrumentedReconciler{}
+
+func newInstrumentedReconciler(name string, reconciler reconcile.Reconciler) reconcile.Reconciler {
+	return &instrumentedReconciler{
+		name:       name,
+		reconciler: reconciler,
+	}
+}
+
+func (r *instrumentedReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	result, err := r.reconciler.Reconcile(ctx, request)
+
+	// The label is resolved once reconciled, as the logical cluster may have been bound or unbound
+	cluster := workspaces.label(request.ClusterName)
+	reconcileDuration.WithLabelValues(r.name, cluster).Observe(time.Since(start).Seconds())
+	switch {
+	case err != nil:
+		reconcileTotal.WithLabelValues(r.name, cluster, resultError).Inc()
+		reconcileErrors.WithLabelValues(r.name, cluster).Inc()
+	case result.Requeue || result.RequeueAfter > 0:
+		reconcileTotal.WithLabelValues(r.name, cluster, resultRequeue).Inc()
+	default:
+		reconcileTotal.WithLabelValues(r.name, cluster, resultSuccess).Inc()
+	}
+
+	return result, err
+}
+
+// maxLogicalClusters returns the maximum number of logical clusters the per-workspace metrics are labelled with.
+func maxLogicalClusters(cfg *config.Holder) int32 {
+	return cfg.Get().Service.Metrics.MaxLogicalClustersOrDefault()
+}
+
+// observePlatformReady records the duration from the APIBinding being bound to the default integration platform
+// being ready.
+func observePlatformReady(binding *apisv1alpha1.APIBinding) {
+	if bound := conditions.Get(binding, apisv1alpha1.InitialBindingCompleted); bound != nil && bound.Status == corev1.ConditionTrue {
+		platformReadyDuration.Observe(time.Since(bound.LastTransitionTime.Time).Seconds())
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+// RegistryProvisioner provisions the container registry organization, i.e., a repository path,
+// and the registry credentials scoped to it, of each consumer workspace. It's pluggable, so that
+// the organization and the credentials can be created using the API of the registry.
+type RegistryProvisioner interface {
+	// Provision provisions the registry organization and credentials of the consumer workspace.
+	Provision(ctx context.Context, registry *config.RegistryProvisioning, cluster logicalcluster.Name) (*ProvisionedRegistry, error)
+	// Deprovision deletes the registry organization and credentials of the consumer workspace, if any.
+	Deprovision(ctx context.Context, registry *config.RegistryProvisioning, cluster logicalcluster.Name) error
+}
+
+// ProvisionedRegistry is the registry organization and credentials of a consumer workspace.
+type ProvisionedRegistry struct {
+	// The registry organization of the consumer workspace.
+	Organization string
+	// The kubernetes.io/dockerconfigjson data of the credentials, to push and pull from the registry
+	// organization, or nil if the consumer workspace is not given credentials.
+	Credentials map[string][]byte
+}
+
+// SecretRegistryProvisioner is the RegistryProvisioner that reads the registry credentials of the consumer
+// workspaces from pre-provisioned Secrets, in the service workspace, and that does not create the registry
+// organizations, that are expected to be created on push.
+type SecretRegistryProvisioner struct {
+	client kubernetes.Interface
+}
+
+var _ RegistryProvisioner = &SecretRegistryProvisioner{}
+
+// NewSecretRegistryProvisioner returns a SecretRegistryProvisioner, using a client for the service workspace.
+func NewSecretRegistryProvisioner(client kubernetes.Interface) *SecretRegistryProvisioner {
+	return &SecretRegistryProvisioner{
+		client: client,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Provision returns the organization of the consumer workspace, and the credentials of its Secret. The Secret
+// shared by all the consumer workspaces is only used when the registry provisioning explicitly allows it.
+// No credentials are returned when none of the Secrets exists, rather than failing the provisioning of the
+// consumer workspace, so that the credentials configured by the default integration platform, if any, are used.
+func (p *SecretRegistryProvisioner) Provision(ctx context.Context, registry *config.RegistryProvisioning, cluster logicalcluster.Name) (*ProvisionedRegistry, error) {
+	provisioned := &ProvisionedRegistry{
+		Organization: registry.OrganizationPrefix + cluster.String(),
+	}
+
+	ref := registry.CredentialsSecret
+	secrets := p.client.CoreV1().Secrets(ref.Namespace)
+
+	name := ref.Name + "-" + cluster.String()
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) && registry.AllowSharedCredentials {
+		name = ref.Name
+		secret, err = secrets.Get(ctx, name, metav1.GetOptions{})
+	}
+	if errors.IsNotFound(err) {
+		Log.Info("Registry credentials Secret not found in the service workspace", "logical-cluster", cluster, "namespace", ref.Namespace, "name", name)
+		return provisioned, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting registry credentials from the service workspace: %w", err)
+	}
+
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		return nil, fmt.Errorf("registry credentials Secret %s/%s must be of type %s", ref.Namespace, name, corev1.SecretTypeDockerConfigJson)
+	}
+	provisioned.Credentials = secret.Data
+
+	return provisioned, nil
+}
+
+// Deprovision does nothing, as the Secrets are managed by the service administrators.
+func (p *SecretRegistryProvisioner) Deprovision(_ context.Context, _ *config.RegistryProvisioning, _ logicalcluster.Name) error {
+	return nil
+}
+
+// provisionRegistry provisions the registry organization and credentials of the consumer workspace, copies the
+// credentials, if any, into the platform namespace, and configures the platform build registry with them.
+func (r *camelKReconciler) provisionRegistry(ctx context.Context, registry *config.RegistryProvisioning, ip *config.IntegrationPlatform, cluster logicalcluster.Name) error {
+	provisioned, err := r.registry.Provision(ctx, registry, cluster)
+	if err != nil {
+		return err
+	}
+	ip.Spec.Build.Registry.Organization = provisioned.Organization
+
+	if provisioned.Credentials == nil {
+		return nil
+	}
+
+	secret := corev1ac.Secret(registry.SecretName, ip.Namespace).
+		WithType(corev1.SecretTypeDockerConfigJson).
+		WithData(provisioned.Credentials)
+	_, err = r.client.CoreV1().Secrets(ip.Namespace).
+		Apply(ctx, secret, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
+	if err != nil {
+		return err
+	}
+	ip.Spec.Build.Registry.Secret = registry.SecretName
+
+	return nil
+}
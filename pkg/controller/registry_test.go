@@ -0,0 +1,84 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+func TestSecretRegistryProvisioner(t *testing.T) {
+	secret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "camel-kcp",
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(name)},
+		}
+	}
+	provisioner := NewSecretRegistryProvisioner(fake.NewSimpleClientset(secret("registry"), secret("registry-tenant")))
+	registry := func(allowShared bool) *config.RegistryProvisioning {
+		return &config.RegistryProvisioning{
+			OrganizationPrefix:     "tenants/",
+			CredentialsSecret:      config.SecretReference{Namespace: "camel-kcp", Name: "registry"},
+			AllowSharedCredentials: allowShared,
+		}
+	}
+
+	t.Run("workspace credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p, err := provisioner.Provision(context.Background(), registry(false), logicalcluster.Name("tenant"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(p.Organization).To(Equal("tenants/tenant"))
+		g.Expect(p.Credentials).To(HaveKeyWithValue(corev1.DockerConfigJsonKey, []byte("registry-tenant")))
+	})
+
+	t.Run("missing workspace credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p, err := provisioner.Provision(context.Background(), registry(false), logicalcluster.Name("other"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(p.Organization).To(Equal("tenants/other"))
+		g.Expect(p.Credentials).To(BeNil())
+	})
+
+	t.Run("shared credentials", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p, err := provisioner.Provision(context.Background(), registry(true), logicalcluster.Name("other"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(p.Credentials).To(HaveKeyWithValue(corev1.DockerConfigJsonKey, []byte("registry")))
+
+		p, err = provisioner.Provision(context.Background(), registry(true), logicalcluster.Name("tenant"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(p.Credentials).To(HaveKeyWithValue(corev1.DockerConfigJsonKey, []byte("registry-tenant")))
+	})
+}
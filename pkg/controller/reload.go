@@ -0,0 +1,60 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/workqueue"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+
+	"github.com/apache/camel-kcp/pkg/config"
+)
+
+// configurationReloaded is a source that triggers the reconciliation of all the APIBindings
+// every time the service configuration is reloaded, so that the changes are rolled out.
+type configurationReloaded struct {
+	cfg    *config.Holder
+	client ctrl.Reader
+}
+
+var _ source.Source = &configurationReloaded{}
+
+func (s *configurationReloaded) Start(ctx context.Context, h handler.EventHandler, q workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+	reloaded := s.cfg.Subscribe(ctx)
+	go func() {
+		for range reloaded {
+			bindings := &apisv1alpha1.APIBindingList{}
+			if err := s.client.List(ctx, bindings); err != nil {
+				Log.Error(err, "Error listing APIBindings to roll out the reloaded configuration")
+				continue
+			}
+			for i := range bindings.Items {
+				h.Generic(event.GenericEvent{Object: &bindings.Items[i]}, q)
+			}
+		}
+	}()
+	return nil
+}
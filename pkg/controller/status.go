@@ -0,0 +1,223 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// The conditions reporting the provisioning state of a consumer workspace.
+const (
+	// PlatformReady reports whether the default integration platform is provisioned.
+	PlatformReady = "PlatformReady"
+	// PlacementReady reports whether the default placement is provisioned.
+	PlacementReady = "PlacementReady"
+	// KaotoReady reports whether the Kaoto resources are provisioned.
+	KaotoReady = "KaotoReady"
+	// AddOnReady reports whether the resources of an add-on are provisioned.
+	AddOnReady = "AddOnReady"
+	// ManifestsReady reports whether the resources of the manifests are applied.
+	ManifestsReady = "ManifestsReady"
+)
+
+const (
+	reasonProvisioned        = "Provisioned"
+	reasonProvisioningFailed = "ProvisioningFailed"
+	reasonWaitingForAPIs     = "WaitingForAPIs"
+	reasonNotReady           = "NotReady"
+)
+
+const (
+	// ConditionsAnnotation is the APIBinding annotation holding the JSON encoded provisioning conditions.
+	ConditionsAnnotation = "camel-kcp.apache.org/conditions"
+	// APIExportLabel is the label of the status ConfigMaps, set to the name of the bound APIExport.
+	APIExportLabel = "camel-kcp.apache.org/api-export"
+	// LogicalClusterLabel is the label of the status ConfigMaps, set to the consumer logical cluster.
+	LogicalClusterLabel = "camel-kcp.apache.org/logical-cluster"
+
+	conditionsKey = "conditions"
+)
+
+// StatusReporter aggregates the provisioning conditions of all the consumer workspaces into the service workspace.
+// The conditions of each APIBinding are stored into a ConfigMap, labelled with the APIExport name and the consumer
+// logical cluster, so that they can be listed, e.g.:
+//
+//	kubectl get configmaps -n camel-kcp -l camel-kcp.apache.org/api-export=camel-k
+type StatusReporter struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewStatusReporter returns a StatusReporter that stores the conditions into the given namespace,
+// using a client for the service workspace.
+func NewStatusReporter(client kubernetes.Interface, namespace string) *StatusReporter {
+	return &StatusReporter{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;patch;delete
+
+func (s *StatusReporter) report(ctx context.Context, apiExport string, cluster logicalcluster.Name, conditions []metav1.Condition) error {
+	if s == nil {
+		return nil
+	}
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+	configMap := corev1ac.ConfigMap(statusConfigMapName(apiExport, cluster), s.namespace).
+		WithLabels(map[string]string{
+			APIExportLabel:      apiExport,
+			LogicalClusterLabel: cluster.String(),
+		}).
+		WithData(map[string]string{
+			conditionsKey: string(data),
+		})
+	_, err = s.client.CoreV1().ConfigMaps(s.namespace).
+		Apply(ctx, configMap, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
+	if err != nil {
+		return fmt.Errorf("error reporting status into the service workspace: %w", err)
+	}
+	return nil
+}
+
+func (s *StatusReporter) remove(ctx context.Context, apiExport string, cluster logicalcluster.Name) error {
+	if s == nil {
+		return nil
+	}
+	err := s.client.CoreV1().ConfigMaps(s.namespace).Delete(ctx, statusConfigMapName(apiExport, cluster), metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("error removing status from the service workspace: %w", err)
+	}
+	return nil
+}
+
+func statusConfigMapName(apiExport string, cluster logicalcluster.Name) string {
+	return apiExport + "-" + cluster.String()
+}
+
+// conditionsOf returns the conditions reported on the APIBinding, or nil if none or if they cannot be decoded.
+func conditionsOf(binding *apisv1alpha1.APIBinding) []metav1.Condition {
+	var conditions []metav1.Condition
+	if value, ok := binding.Annotations[ConditionsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &conditions); err != nil {
+			return nil
+		}
+	}
+	return conditions
+}
+
+// notReadyError is returned by the provisioning steps, when the provisioned resource exists but is not yet ready.
+type notReadyError struct {
+	message string
+}
+
+func (e *notReadyError) Error() string {
+	return e.message
+}
+
+func notReady(format string, a ...interface{}) error {
+	return &notReadyError{message: fmt.Sprintf(format, a...)}
+}
+
+func isNotReady(err error) bool {
+	var notReady *notReadyError
+	return goerrors.As(err, &notReady)
+}
+
+// isReady returns whether the APIBinding has been provisioned, and all its conditions are true.
+func isReady(binding *apisv1alpha1.APIBinding) bool {
+	if _, ok := binding.Annotations[ConditionsAnnotation]; !ok {
+		return false
+	}
+	for _, condition := range conditionsOf(binding) {
+		if condition.Status != metav1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// setCondition sets the condition of the given type, depending on the error returned by the provisioning step.
+func setCondition(conditions *[]metav1.Condition, binding *apisv1alpha1.APIBinding, conditionType string, err error) {
+	condition := metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonProvisioned,
+		ObservedGeneration: binding.Generation,
+	}
+	if errors.IsNotFound(err) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonWaitingForAPIs
+		condition.Message = "Bound APIs are not yet found"
+	} else if isNotReady(err) {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonNotReady
+		condition.Message = err.Error()
+	} else if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonProvisioningFailed
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// reportStatus reports the conditions on the APIBinding, in the consumer workspace,
+// and aggregates them into the service workspace.
+func (r *reconciler) reportStatus(ctx context.Context, binding *apisv1alpha1.APIBinding, apiExport string, conditions []metav1.Condition) error {
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+
+	if binding.Annotations[ConditionsAnnotation] != string(data) {
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"annotations": map[string]string{
+					ConditionsAnnotation: string(data),
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := r.client.Patch(ctx, binding, ctrl.RawPatch(types.MergePatchType, patch)); err != nil {
+			return fmt.Errorf("error reporting status on APIBinding: %w", err)
+		}
+	}
+
+	return r.status.report(ctx, apiExport, logicalcluster.From(binding), conditions)
+}
@@ -0,0 +1,92 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+// WorkspacePathLabel is the label of the workspace path ConfigMaps, set to true.
+const WorkspacePathLabel = "camel-kcp.apache.org/workspace-path"
+
+const workspacePathKey = "path"
+
+// WorkspacePaths records the paths of the consumer workspaces into the service workspace, so that the overrides
+// can select the workspaces by their paths. The paths are read by the initializer from the LogicalCluster of the
+// initializing workspaces, as the LogicalClusters cannot be claimed by the APIExports, and the tenants can change
+// the annotations of their APIBindings. The path of each workspace is stored into a ConfigMap, e.g.:
+//
+//	kubectl get configmaps -n camel-kcp -l camel-kcp.apache.org/workspace-path -L camel-kcp.apache.org/logical-cluster
+type WorkspacePaths struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewWorkspacePaths returns a WorkspacePaths that stores the paths into the given namespace,
+// using a client for the service workspace.
+func NewWorkspacePaths(client kubernetes.Interface, namespace string) *WorkspacePaths {
+	return &WorkspacePaths{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;patch
+
+func (w *WorkspacePaths) record(ctx context.Context, cluster logicalcluster.Name, path logicalcluster.Path) error {
+	configMap := corev1ac.ConfigMap(workspacePathConfigMapName(cluster), w.namespace).
+		WithLabels(map[string]string{
+			WorkspacePathLabel:  "true",
+			LogicalClusterLabel: cluster.String(),
+		}).
+		WithData(map[string]string{
+			workspacePathKey: path.String(),
+		})
+	_, err := w.client.CoreV1().ConfigMaps(w.namespace).
+		Apply(ctx, configMap, metav1.ApplyOptions{FieldManager: applyManager, Force: true})
+	if err != nil {
+		return fmt.Errorf("error recording workspace path into the service workspace: %w", err)
+	}
+	return nil
+}
+
+// get returns the recorded path of the consumer workspace, or an empty path if it is not recorded.
+func (w *WorkspacePaths) get(ctx context.Context, cluster logicalcluster.Name) (logicalcluster.Path, error) {
+	if w == nil {
+		return logicalcluster.None, nil
+	}
+	configMap, err := w.client.CoreV1().ConfigMaps(w.namespace).Get(ctx, workspacePathConfigMapName(cluster), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return logicalcluster.None, nil
+	} else if err != nil {
+		return logicalcluster.None, fmt.Errorf("error reading workspace path from the service workspace: %w", err)
+	}
+	return logicalcluster.NewPath(configMap.Data[workspacePathKey]), nil
+}
+
+func workspacePathConfigMapName(cluster logicalcluster.Name) string {
+	return "workspace-" + cluster.String()
+}
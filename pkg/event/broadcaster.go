@@ -0,0 +1,94 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+// NewClusterAwareBroadcaster returns a broadcaster whose recorders annotate events with the logical cluster
+// of their involved objects, so that the sink returned by NewClusterAwareSink can route them to the
+// corresponding workspaces.
+// Recording to any other sink is a no-op, as it cannot know in which logical cluster events must be created.
+// This is the case of the sink that controller-runtime managers start recording to.
+func NewClusterAwareBroadcaster() record.EventBroadcaster {
+	return &clusterAwareBroadcaster{
+		EventBroadcaster: record.NewBroadcaster(),
+	}
+}
+
+type clusterAwareBroadcaster struct {
+	record.EventBroadcaster
+}
+
+var _ record.EventBroadcaster = &clusterAwareBroadcaster{}
+
+func (b *clusterAwareBroadcaster) StartRecordingToSink(sink record.EventSink) watch.Interface {
+	if _, ok := sink.(*clusterAwareSink); !ok {
+		return watch.NewEmptyWatch()
+	}
+	return b.EventBroadcaster.StartRecordingToSink(sink)
+}
+
+func (b *clusterAwareBroadcaster) NewRecorder(scheme *runtime.Scheme, source corev1.EventSource) record.EventRecorder {
+	return &clusterAwareRecorder{
+		recorder: b.EventBroadcaster.NewRecorder(scheme, source),
+	}
+}
+
+type clusterAwareRecorder struct {
+	recorder record.EventRecorder
+}
+
+var _ record.EventRecorder = &clusterAwareRecorder{}
+
+func (r *clusterAwareRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.recorder.AnnotatedEventf(object, withCluster(object, nil), eventtype, reason, "%s", message)
+}
+
+func (r *clusterAwareRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.recorder.AnnotatedEventf(object, withCluster(object, nil), eventtype, reason, messageFmt, args...)
+}
+
+func (r *clusterAwareRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.recorder.AnnotatedEventf(object, withCluster(object, annotations), eventtype, reason, messageFmt, args...)
+}
+
+// withCluster returns a copy of the annotations, with the logical cluster annotation of the object added.
+func withCluster(object runtime.Object, annotations map[string]string) map[string]string {
+	o, err := meta.Accessor(object)
+	if err != nil {
+		return annotations
+	}
+	cluster := logicalcluster.From(o)
+	if cluster.Empty() {
+		return annotations
+	}
+	a := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		a[k] = v
+	}
+	a[logicalcluster.AnnotationKey] = cluster.String()
+	return a
+}
@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+// NewClusterAwareSink returns a sink that writes events into the logical cluster of their involved objects,
+// using the given cluster-aware client, e.g., that of an APIExport virtual workspace.
+// Events must be recorded using a recorder created by a broadcaster returned by NewClusterAwareBroadcaster.
+func NewClusterAwareSink(client corev1client.EventsGetter) record.EventSink {
+	return &clusterAwareSink{
+		client: client,
+	}
+}
+
+type clusterAwareSink struct {
+	client corev1client.EventsGetter
+}
+
+var _ record.EventSink = &clusterAwareSink{}
+
+func (s *clusterAwareSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	ctx, err := contextFor(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Events(event.Namespace).Create(ctx, withoutCluster(event), metav1.CreateOptions{})
+}
+
+func (s *clusterAwareSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	ctx, err := contextFor(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Events(event.Namespace).Update(ctx, withoutCluster(event), metav1.UpdateOptions{})
+}
+
+func (s *clusterAwareSink) Patch(event *corev1.Event, data []byte) (*corev1.Event, error) {
+	ctx, err := contextFor(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.Events(event.Namespace).Patch(ctx, event.Name, types.StrategicMergePatchType, data, metav1.PatchOptions{})
+}
+
+func contextFor(event *corev1.Event) (context.Context, error) {
+	cluster := logicalcluster.From(event)
+	if cluster.Empty() {
+		// The event won't be retried
+		return nil, &rest.RequestConstructionError{
+			Err: fmt.Errorf("cannot determine logical cluster for event %s/%s", event.Namespace, event.Name),
+		}
+	}
+	return kontext.WithCluster(context.Background(), cluster), nil
+}
+
+// withoutCluster returns a copy of the event, with the logical cluster annotation removed,
+// as it's set by the server.
+func withoutCluster(event *corev1.Event) *corev1.Event {
+	if _, ok := event.Annotations[logicalcluster.AnnotationKey]; !ok {
+		return event
+	}
+	e := event.DeepCopy()
+	delete(e.Annotations, logicalcluster.AnnotationKey)
+	return e
+}
@@ -17,7 +17,9 @@ limitations under the License.
 
 package platform
 
-import "github.com/apache/camel-k/pkg/platform"
+import (
+	"github.com/apache/camel-k/pkg/platform"
+)
 
 const (
 	DefaultPlatformName  = platform.DefaultPlatformName
@@ -27,7 +29,10 @@ const (
 	OperatorNamespaceEnvVariable = "NAMESPACE"
 )
 
-var (
-	GetOperatorNamespace = platform.GetOperatorNamespace
-	GetOperatorPodName   = platform.GetOperatorPodName
-)
+var GetOperatorPodName = platform.GetOperatorPodName
+
+// SetOperatorImage sets the container image of the operator, that the github.com/apache/camel-k/pkg/platform package
+// defaults to, e.g., for the builder Pods.
+func SetOperatorImage(image string) {
+	platform.OperatorImage = image
+}
@@ -55,6 +55,10 @@ KCP_CONTROL_CLUSTER_NAME="${KIND_CLUSTER_PREFIX}control"
 : ${KCP_VERSION:="main"}
 KCP_SYNCER_IMAGE="ghcr.io/kcp-dev/kcp/syncer:${KCP_VERSION}"
 
+: ${KNATIVE_VERSION:="knative-v1.8.0"}
+BATCH_RESOURCES="jobs.batch,cronjobs.batch"
+KNATIVE_RESOURCES="services.serving.knative.dev,brokers.eventing.knative.dev,triggers.eventing.knative.dev,channels.messaging.knative.dev,subscriptions.messaging.knative.dev,sinkbindings.sources.knative.dev"
+
 for ((i=1;i<=NUM_CLUSTERS;i++))
 do
 	CLUSTERS="${CLUSTERS}${KIND_CLUSTER_PREFIX}${i} "
@@ -108,6 +112,23 @@ createSyncTarget() {
   ${KUSTOMIZE_BIN} build "${dir}" | kubectl --kubeconfig ${TEMP_DIR}/"${1}".kubeconfig apply --server-side -f -
 }
 
+installKnative() {
+  kubeconfig=$1
+  echo "Deploying Knative Serving and Eventing"
+  kubectl --kubeconfig "${kubeconfig}" apply -f https://github.com/knative/serving/releases/download/"${KNATIVE_VERSION}"/serving-crds.yaml
+  kubectl --kubeconfig "${kubeconfig}" apply -f https://github.com/knative/serving/releases/download/"${KNATIVE_VERSION}"/serving-core.yaml
+  kubectl --kubeconfig "${kubeconfig}" apply -f https://github.com/knative/net-kourier/releases/download/"${KNATIVE_VERSION}"/kourier.yaml
+  kubectl --kubeconfig "${kubeconfig}" patch configmap/config-network -n knative-serving --type merge \
+    -p '{"data":{"ingress-class":"kourier.ingress.networking.knative.dev"}}'
+  kubectl --kubeconfig "${kubeconfig}" apply -f https://github.com/knative/eventing/releases/download/"${KNATIVE_VERSION}"/eventing-crds.yaml
+  kubectl --kubeconfig "${kubeconfig}" apply -f https://github.com/knative/eventing/releases/download/"${KNATIVE_VERSION}"/eventing-core.yaml
+  kubectl --kubeconfig "${kubeconfig}" apply -f https://github.com/knative/eventing/releases/download/"${KNATIVE_VERSION}"/in-memory-channel.yaml
+  kubectl --kubeconfig "${kubeconfig}" apply -f https://github.com/knative/eventing/releases/download/"${KNATIVE_VERSION}"/mt-channel-broker.yaml
+  echo "Waiting for Knative deployments to be ready ..."
+  kubectl --kubeconfig "${kubeconfig}" -n knative-serving wait --timeout=300s --for=condition=Available deployments --all
+  kubectl --kubeconfig "${kubeconfig}" -n knative-eventing wait --timeout=300s --for=condition=Available deployments --all
+}
+
 # Delete existing KinD clusters
 clusterCount=$(${KIND_BIN} get clusters | grep ${KIND_CLUSTER_PREFIX} | wc -l)
 if ! [[ $clusterCount =~ "0" ]] ; then
@@ -168,6 +189,14 @@ ${KUBECTL_KCP_BIN} workspace create camel-kcp --type universal --enter || ${KUBE
 ${KUBECTL_KCP_BIN} bind apiexport root:compute:kubernetes --name kubernetes
 kubectl wait --timeout=300s --for=condition=Ready=true apibinding kubernetes
 
+# The namespace where the leader election lease is created
+kubectl create namespace camel-kcp --dry-run=client -o yaml | kubectl apply -f -
+
+# Install Knative APIExport, that the Camel K APIExport claims, and get its identity hash
+kubectl apply --server-side -f config/kcp/knative.apiresourceschemas.yaml -f config/kcp/api_export_knative.yaml
+kubectl wait --timeout=300s --for=condition=IdentityValid=true apiexport knative
+knativeIdentityHash=$(kubectl get apiexport knative -o json | jq -r .status.identityHash)
+
 # Create control and data plane locations
 cat <<EOF | kubectl apply -f -
 apiVersion: scheduling.kcp.io/v1alpha1
@@ -214,11 +243,17 @@ createSyncTarget $KCP_CONTROL_CLUSTER_NAME "$registry_addr:$registry_port" "cont
 kubectl label --overwrite synctarget "control" "org.apache.camel/control-plane="
 kubectl wait --timeout=300s --for=condition=Ready=true synctargets "control"
 
+# Install batch APIExport, with the schemas pulled from the control plane cluster, and get its identity hash
+make batch-apiresourceschemas BATCH_KUBECONFIG="$(pwd)/${TEMP_DIR}/${KCP_CONTROL_CLUSTER_NAME}.kubeconfig"
+${KUSTOMIZE_BIN} build config/kcp/batch | kubectl apply --server-side -f -
+kubectl wait --timeout=300s --for=condition=IdentityValid=true apiexport batch
+batchIdentityHash=$(kubectl get apiexport batch -o json | jq -r .status.identityHash)
+
 # Create data plane sync targets and wait for them to be ready
 echo "Creating $NUM_CLUSTERS kcp SyncTarget cluster(s)"
 
 for cluster in $CLUSTERS; do
-  createSyncTarget "$cluster" "$registry_addr:$registry_port" "$cluster" "--feature-gates=KCPSyncerTunnel=true" emptyPatch
+  createSyncTarget "$cluster" "$registry_addr:$registry_port" "$cluster" "--feature-gates=KCPSyncerTunnel=true --apiexports=root:compute:kubernetes,root:camel-kcp:knative,root:camel-kcp:batch --resources=${KNATIVE_RESOURCES},${BATCH_RESOURCES}" emptyPatch
   kubectl label --overwrite synctarget "$cluster" "org.apache.camel/data-plane="
 
   echo "Deploying Ingress controller to ${cluster}"
@@ -228,13 +263,17 @@ for cluster in $CLUSTERS; do
   kubectl --kubeconfig "${kubeconfig}" annotate ingressclass nginx "ingressclass.kubernetes.io/is-default-class=true"
   echo "Waiting for deployments to be ready ..."
   kubectl --kubeconfig "${kubeconfig}" -n ingress-nginx wait --timeout=300s --for=condition=Available deployments --all
+
+  installKnative "${kubeconfig}"
 done
 kubectl wait --timeout=300s --for=condition=Ready=true synctargets ${CLUSTERS}
 
 # Install APIExport
 ${KUSTOMIZE_BIN} fn run config/kcp --image gcr.io/kpt-fn/apply-setters:v0.2.0 -- \
 kubernetes-identity-hash="$kubernetesIdentityHash" \
-scheduling-identity-hash="$schedulingIdentityHash"
+scheduling-identity-hash="$schedulingIdentityHash" \
+knative-identity-hash="$knativeIdentityHash" \
+batch-identity-hash="$batchIdentityHash"
 ${KUSTOMIZE_BIN} build config/kcp | kubectl apply --server-side -f -
 
 echo ""
@@ -0,0 +1,76 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+
+	. "github.com/apache/camel-kcp/test/support"
+)
+
+func TestCamelKAPIUnbinding(t *testing.T) {
+	test := With(t)
+	test.T().Parallel()
+
+	// Create the test workspace, that's only ready once it's initialized
+	workspace := test.NewTestWorkspace(OfType(CamelWorkspaceType))
+
+	cluster := logicalcluster.NewPath(workspace.Spec.Cluster)
+
+	// The default integration platform must be provisioned
+	ip, err := test.Client().CamelV1().IntegrationPlatforms("camel-k").Get(Inside(test.Ctx(), workspace), "camel-k", metav1.GetOptions{})
+	test.Expect(err).NotTo(HaveOccurred())
+	test.Expect(ip.Status.Phase).To(Equal(camelv1.IntegrationPlatformPhaseReady))
+
+	// Unbind the Camel K APIs
+	err = test.Client().Kcp().Cluster(cluster).ApisV1alpha1().APIBindings().Delete(test.Ctx(), "camel-k", metav1.DeleteOptions{})
+	test.Expect(err).NotTo(HaveOccurred())
+
+	// The APIBinding is only deleted once the provisioned resources are cleaned up
+	test.Eventually(func() error {
+		_, err := test.Client().Kcp().Cluster(cluster).ApisV1alpha1().APIBindings().Get(test.Ctx(), "camel-k", metav1.GetOptions{})
+		return err
+	}, TestTimeoutShort).Should(WithTransform(errors.IsNotFound, BeTrue()))
+
+	// The default integration platform must be deleted
+	test.Eventually(func() error {
+		_, err := test.Client().CamelV1().IntegrationPlatforms("camel-k").Get(Inside(test.Ctx(), workspace), "camel-k", metav1.GetOptions{})
+		return err
+	}, TestTimeoutShort).Should(WithTransform(errors.IsNotFound, BeTrue()))
+
+	// The default placement must be deleted
+	test.Eventually(func() error {
+		_, err := test.Client().Kcp().Cluster(cluster).SchedulingV1alpha1().Placements().Get(test.Ctx(), "default", metav1.GetOptions{})
+		return err
+	}, TestTimeoutShort).Should(WithTransform(errors.IsNotFound, BeTrue()))
+
+	// The operator namespace must be deleted
+	test.Eventually(func() error {
+		_, err := test.Client().Core().Cluster(cluster).CoreV1().Namespaces().Get(test.Ctx(), "camel-k", metav1.GetOptions{})
+		return err
+	}, TestTimeoutMedium).Should(WithTransform(errors.IsNotFound, BeTrue()))
+}
@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	traitv1 "github.com/apache/camel-k/pkg/apis/camel/v1/trait"
+
+	. "github.com/apache/camel-kcp/test/support"
+)
+
+func TestCronIntegration(t *testing.T) {
+	test := With(t)
+	test.T().Parallel()
+
+	// Create the test workspace
+	workspace := test.NewTestWorkspace(OfType(CamelWorkspaceType))
+
+	// Create a namespace
+	namespace := test.NewTestNamespace(InWorkspace[*corev1.Namespace](workspace))
+
+	// Create the Integration
+	name := "hello-cron"
+	integration := &camelv1.Integration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: camelv1.IntegrationSpec{
+			Flows: []camelv1.Flow{
+				Flow(test, `
+from:
+  uri: cron:tab?schedule=* * * * *
+  steps:
+    - setBody:
+        constant: Happy e2e testing!
+    - to: log:info
+`),
+			},
+			Traits: camelv1.Traits{
+				Cron: &traitv1.CronTrait{
+					Trait: traitv1.Trait{
+						Enabled: pointer.Bool(true),
+					},
+					Fallback: pointer.Bool(false),
+				},
+			},
+		},
+	}
+
+	_, err := test.Client().CamelV1().Integrations(namespace.Name).
+		Create(Inside(test.Ctx(), workspace), integration, metav1.CreateOptions{})
+	test.Expect(err).NotTo(HaveOccurred())
+
+	test.Eventually(Integration(test, namespace, name), TestTimeoutLong).
+		Should(And(
+			WithTransform(ConditionStatus(camelv1.IntegrationConditionCronJobAvailable), Equal(corev1.ConditionTrue)),
+			WithTransform(ConditionStatus(camelv1.IntegrationConditionReady), Equal(corev1.ConditionTrue)),
+		))
+
+	// The CronJob status is synced back from the sync target, once a Job has been scheduled
+	test.Eventually(CronJob(test, namespace, name), TestTimeoutMedium).
+		Should(WithTransform(CronJobLastScheduleTime, Not(BeNil())))
+}
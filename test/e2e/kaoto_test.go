@@ -0,0 +1,77 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	. "github.com/apache/camel-kcp/test/support"
+)
+
+func TestKaotoResourcesDrift(t *testing.T) {
+	test := With(t)
+	test.T().Parallel()
+
+	// Create the test workspace, that's only ready once it's initialized
+	workspace := test.NewTestWorkspace(OfType(CamelWorkspaceType))
+
+	cluster := logicalcluster.NewPath(workspace.Spec.Cluster)
+
+	// The Kaoto resources must be provisioned
+	deployment, err := test.Client().Core().Cluster(cluster).AppsV1().Deployments("kaoto").Get(test.Ctx(), "kaoto-ui", metav1.GetOptions{})
+	test.Expect(err).NotTo(HaveOccurred())
+	clusterRole, err := test.Client().Core().Cluster(cluster).RbacV1().ClusterRoles().Get(test.Ctx(), "kaoto", metav1.GetOptions{})
+	test.Expect(err).NotTo(HaveOccurred())
+	rules := clusterRole.Rules
+	test.Expect(rules).To(HaveLen(3))
+
+	// Delete the Kaoto UI Deployment
+	err = test.Client().Core().Cluster(cluster).AppsV1().Deployments("kaoto").Delete(test.Ctx(), "kaoto-ui", metav1.DeleteOptions{})
+	test.Expect(err).NotTo(HaveOccurred())
+
+	// The Kaoto UI Deployment must be re-created
+	test.Eventually(func() (types.UID, error) {
+		d, err := test.Client().Core().Cluster(cluster).AppsV1().Deployments("kaoto").Get(test.Ctx(), "kaoto-ui", metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		return d.UID, nil
+	}, TestTimeoutShort).ShouldNot(Or(BeEmpty(), Equal(deployment.UID)))
+
+	// Remove all the rules of the Kaoto ClusterRole, but the first one
+	clusterRole.Rules = clusterRole.Rules[:1]
+	_, err = test.Client().Core().Cluster(cluster).RbacV1().ClusterRoles().Update(test.Ctx(), clusterRole, metav1.UpdateOptions{})
+	test.Expect(err).NotTo(HaveOccurred())
+
+	// The rules of the Kaoto ClusterRole must be restored
+	test.Eventually(func() ([]rbacv1.PolicyRule, error) {
+		cr, err := test.Client().Core().Cluster(cluster).RbacV1().ClusterRoles().Get(test.Ctx(), "kaoto", metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return cr.Rules, nil
+	}, TestTimeoutShort).Should(Equal(rules))
+}
@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package support
+
+import (
+	"github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+)
+
+func APIBinding(t Test, workspace *tenancyv1alpha1.Workspace, name string) func(g gomega.Gomega) *apisv1alpha1.APIBinding {
+	return func(g gomega.Gomega) *apisv1alpha1.APIBinding {
+		binding, err := t.Client().Kcp().Cluster(logicalcluster.NewPath(workspace.Spec.Cluster)).ApisV1alpha1().APIBindings().Get(t.Ctx(), name, metav1.GetOptions{})
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		return binding
+	}
+}
+
+func APIBindingPhase(binding *apisv1alpha1.APIBinding) apisv1alpha1.APIBindingPhaseType {
+	return binding.Status.Phase
+}
+
+// BindAPIExport binds the APIExport of the given name, from the service workspace, into the workspace,
+// accepting all its permission claims, and waits for the APIBinding to be bound.
+func BindAPIExport(t Test, workspace *tenancyv1alpha1.Workspace, name string) *apisv1alpha1.APIBinding {
+	t.T().Helper()
+	cluster := logicalcluster.NewPath(workspace.Spec.Cluster)
+
+	export, err := t.Client().Kcp().Cluster(ServiceWorkspace).ApisV1alpha1().APIExports().Get(t.Ctx(), name, metav1.GetOptions{})
+	t.Expect(err).NotTo(gomega.HaveOccurred())
+
+	binding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: apisv1alpha1.APIBindingSpec{
+			Reference: apisv1alpha1.BindingReference{
+				Export: &apisv1alpha1.ExportBindingReference{
+					Path: ServiceWorkspace.String(),
+					Name: name,
+				},
+			},
+		},
+	}
+	for _, claim := range export.Spec.PermissionClaims {
+		binding.Spec.PermissionClaims = append(binding.Spec.PermissionClaims, apisv1alpha1.AcceptablePermissionClaim{
+			PermissionClaim: claim,
+			State:           apisv1alpha1.ClaimAccepted,
+		})
+	}
+
+	_, err = t.Client().Kcp().Cluster(cluster).ApisV1alpha1().APIBindings().Create(t.Ctx(), binding, metav1.CreateOptions{})
+	t.Expect(err).NotTo(gomega.HaveOccurred())
+
+	t.Eventually(APIBinding(t, workspace, name), TestTimeoutShort).
+		Should(gomega.WithTransform(APIBindingPhase, gomega.Equal(apisv1alpha1.APIBindingPhaseBound)))
+
+	binding, err = t.Client().Kcp().Cluster(cluster).ApisV1alpha1().APIBindings().Get(t.Ctx(), name, metav1.GetOptions{})
+	t.Expect(err).NotTo(gomega.HaveOccurred())
+
+	return binding
+}
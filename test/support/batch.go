@@ -0,0 +1,40 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package support
+
+import (
+	"github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+func CronJob(t Test, namespace *corev1.Namespace, name string) func(g gomega.Gomega) *batchv1.CronJob {
+	return func(g gomega.Gomega) *batchv1.CronJob {
+		cronJob, err := t.Client().Core().Cluster(logicalcluster.From(namespace).Path()).BatchV1().CronJobs(namespace.Name).Get(t.Ctx(), name, metav1.GetOptions{})
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		return cronJob
+	}
+}
+
+func CronJobLastScheduleTime(cronJob *batchv1.CronJob) *metav1.Time {
+	return cronJob.Status.LastScheduleTime
+}
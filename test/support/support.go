@@ -33,7 +33,8 @@ import (
 )
 
 const (
-	testWorkspaceName = "TEST_WORKSPACE"
+	testWorkspaceName    = "TEST_WORKSPACE"
+	serviceWorkspaceName = "SERVICE_WORKSPACE"
 
 	clustersKubeConfigDir = "CLUSTERS_KUBECONFIG_DIR"
 
@@ -43,7 +44,8 @@ const (
 )
 
 var (
-	TestWorkspace = getEnvLogicalClusterName(testWorkspaceName, logicalcluster.NewPath("root:camel-k"))
+	TestWorkspace    = getEnvLogicalClusterName(testWorkspaceName, logicalcluster.NewPath("root:camel-k"))
+	ServiceWorkspace = getEnvLogicalClusterName(serviceWorkspaceName, logicalcluster.NewPath("root:camel-kcp"))
 
 	CamelWorkspaceType = tenancyv1alpha1.WorkspaceTypeReference{Name: "camel-k"}
 
@@ -31,6 +31,7 @@ type Syncer struct {
 	namespace       string
 	replicas        int
 	resourcesToSync []string
+	apiExports      []string
 }
 
 var _ Option[*SyncTargetConfig] = (*Syncer)(nil)
@@ -55,6 +56,11 @@ func (s *Syncer) ResourcesToSync(resourcesToSync ...string) *Syncer {
 	return s
 }
 
+func (s *Syncer) APIExports(apiExports ...string) *Syncer {
+	s.apiExports = apiExports
+	return s
+}
+
 // nolint: unused
 // To be removed when the false-positivity is fixed.
 func (s *Syncer) applyTo(config *SyncTargetConfig) error {
@@ -107,7 +107,8 @@ func (t *T) NewTestWorkspace(options ...Option[*tenancyv1alpha1.Workspace]) *ten
 	t.T().Cleanup(func() {
 		deleteTestWorkspace(t, workspace)
 	})
-	t.Eventually(Workspace(t, workspace.Name), TestTimeoutShort).
+	// The workspace is initialized once the APIBindings are provisioned
+	t.Eventually(Workspace(t, workspace.Name), TestTimeoutMedium).
 		Should(gomega.WithTransform(WorkspacePhase, gomega.Equal(corev1alpha1.LogicalClusterPhaseReady)))
 
 	var err error
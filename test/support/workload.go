@@ -151,6 +151,12 @@ func applyKcpWorkloadSync(t Test, config *SyncTargetConfig) (func() error, error
 	syncOptions.KCPNamespace = config.syncer.namespace
 	syncOptions.SyncerImage = config.syncer.image
 	syncOptions.Replicas = config.syncer.replicas
+	if len(config.syncer.resourcesToSync) > 0 {
+		syncOptions.ResourcesToSync = config.syncer.resourcesToSync
+	}
+	if len(config.syncer.apiExports) > 0 {
+		syncOptions.APIExports = config.syncer.apiExports
+	}
 
 	err := syncOptions.Complete([]string{config.name})
 	if err != nil {